@@ -8,6 +8,40 @@ type Timeline interface {
 	Apply(skeleton *Skeleton, time, alpha float32)
 }
 
+// MixBlend controls how a timeline combines its value with the current pose.
+type MixBlend int
+
+const (
+	// MixReplace moves the current pose towards the timeline's value by
+	// alpha.
+	MixReplace MixBlend = iota
+	// MixAdd adds the timeline's offset from the setup pose, scaled by
+	// alpha, on top of the current pose. Rotations add the offset the
+	// shortest way round, and scales multiply. Timelines that have no
+	// offset, such as attachment timelines, replace as usual.
+	MixAdd
+	// mixSum is MixAdd with scales added rather than multiplied, so that
+	// blend spaces can build a weighted average of several animations.
+	mixSum
+)
+
+// blendTimeline is implemented by timelines that support every MixBlend.
+type blendTimeline interface {
+	mix(skeleton *Skeleton, time, alpha float32, blend MixBlend)
+}
+
+// BoneTimeline is implemented by timelines that animate a bone.
+type BoneTimeline interface {
+	Timeline
+	BoneIndex() int
+}
+
+// SlotTimeline is implemented by timelines that animate a slot.
+type SlotTimeline interface {
+	Timeline
+	SlotIndex() int
+}
+
 type RotateTimeline struct {
 	boneIndex int
 	frames    []float32
@@ -22,46 +56,54 @@ func NewRotateTimeline(l int) *RotateTimeline {
 }
 
 func (t *RotateTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
-	frames := t.frames
-	if time < frames[0] {
+	t.mix(skeleton, time, alpha, MixReplace)
+}
+
+func (t *RotateTimeline) mix(skeleton *Skeleton, time, alpha float32, blend MixBlend) {
+	if time < t.frames[0] {
 		return
 	}
 
 	bone := skeleton.Bones[t.boneIndex]
-	if time >= frames[len(frames)-2] {
-		amount := bone.Data.rotation + frames[len(frames)-1] - bone.Rotation
-		for amount > 180 {
-			amount -= 360
-		}
-
-		for amount < -180 {
-			amount += 360
-		}
-		bone.Rotation += amount * alpha
+	if !bone.active {
 		return
 	}
+	angle := t.valueAt(time)
+	if blend != MixReplace {
+		bone.Rotation += wrapAngle(angle) * alpha
+		return
+	}
+	bone.Rotation += wrapAngle(bone.Data.rotation+angle-bone.Rotation) * alpha
+}
+
+// valueAt returns the angle of the timeline at time, relative to the setup
+// pose. Times outside the frames take the value of the nearest frame.
+func (t *RotateTimeline) valueAt(time float32) float32 {
+	frames := t.frames
+	if time < frames[0] {
+		return frames[1]
+	}
+	if time >= frames[len(frames)-2] {
+		return frames[len(frames)-1]
+	}
 
 	frameIndex := binarySearch(frames, time, 2)
 	lastFrameValue := frames[frameIndex-1]
 	frameTime := frames[frameIndex]
 	percent := 1 - (time-frameTime)/(frames[frameIndex-2]-frameTime)
 	percent = t.curve.CurvePercent(frameIndex/2-1, percent)
-	amount := frames[frameIndex+1] - lastFrameValue
-	for amount > 180 {
-		amount -= 360
-	}
-	for amount < -180 {
-		amount += 360
-	}
-	amount = bone.Data.rotation + (lastFrameValue + amount*percent) - bone.Rotation
-	for amount > 180 {
-		amount -= 360
-	}
+	return lastFrameValue + wrapAngle(frames[frameIndex+1]-lastFrameValue)*percent
+}
 
-	for amount < -180 {
-		amount += 360
+// wrapAngle returns the angle in degrees in the range [-180, 180].
+func wrapAngle(angle float32) float32 {
+	for angle > 180 {
+		angle -= 360
+	}
+	for angle < -180 {
+		angle += 360
 	}
-	bone.Rotation += amount * alpha
+	return angle
 }
 
 func binarySearch(values []float32, target float32, step int) int {
@@ -90,10 +132,24 @@ func (t *RotateTimeline) setFrame(index int, time, angle float32) {
 	t.frames[frameIndex+1] = angle
 }
 
-func (t *RotateTimeline) frameCount() int {
+func (t *RotateTimeline) FrameCount() int {
 	return len(t.frames) / 2
 }
 
+func (t *RotateTimeline) BoneIndex() int {
+	return t.boneIndex
+}
+
+// Frame returns the time and angle of the frame at index.
+func (t *RotateTimeline) Frame(index int) (time, angle float32) {
+	return t.frames[index*2], t.frames[index*2+1]
+}
+
+// Curve returns a copy of the timeline's curve.
+func (t *RotateTimeline) Curve() *Curve {
+	return t.curve.clone()
+}
+
 type TranslateTimeline struct {
 	boneIndex int
 	frames    []float32
@@ -107,10 +163,24 @@ func NewTranslateTimeline(l int) *TranslateTimeline {
 	return timeline
 }
 
-func (t *TranslateTimeline) frameCount() int {
+func (t *TranslateTimeline) FrameCount() int {
 	return len(t.frames) / 3
 }
 
+func (t *TranslateTimeline) BoneIndex() int {
+	return t.boneIndex
+}
+
+// Frame returns the time and offset of the frame at index.
+func (t *TranslateTimeline) Frame(index int) (time, x, y float32) {
+	return t.frames[index*3], t.frames[index*3+1], t.frames[index*3+2]
+}
+
+// Curve returns a copy of the timeline's curve.
+func (t *TranslateTimeline) Curve() *Curve {
+	return t.curve.clone()
+}
+
 func (t *TranslateTimeline) setFrame(index int, time, x, y float32) {
 	frameIndex := index * 3
 	t.frames[frameIndex] = time
@@ -119,17 +189,37 @@ func (t *TranslateTimeline) setFrame(index int, time, x, y float32) {
 }
 
 func (t *TranslateTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
-	frames := t.frames
-	if time < frames[0] {
+	t.mix(skeleton, time, alpha, MixReplace)
+}
+
+func (t *TranslateTimeline) mix(skeleton *Skeleton, time, alpha float32, blend MixBlend) {
+	if time < t.frames[0] {
 		return
 	}
 
 	bone := skeleton.Bones[t.boneIndex]
+	if !bone.active {
+		return
+	}
+	x, y := t.valueAt(time)
+	if blend != MixReplace {
+		bone.X += x * alpha
+		bone.Y += y * alpha
+		return
+	}
+	bone.X += (bone.Data.x + x - bone.X) * alpha
+	bone.Y += (bone.Data.y + y - bone.Y) * alpha
+}
 
+// valueAt returns the offset of the timeline at time, relative to the setup
+// pose. Times outside the frames take the value of the nearest frame.
+func (t *TranslateTimeline) valueAt(time float32) (x, y float32) {
+	frames := t.frames
+	if time < frames[0] {
+		return frames[1], frames[2]
+	}
 	if time >= frames[len(frames)-3] {
-		bone.X += (bone.Data.x + frames[len(frames)-2] - bone.X) * alpha
-		bone.Y += (bone.Data.y + frames[len(frames)-1] - bone.Y) * alpha
-		return
+		return frames[len(frames)-2], frames[len(frames)-1]
 	}
 
 	frameIndex := binarySearch(frames, time, 3)
@@ -137,10 +227,9 @@ func (t *TranslateTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
 	lastFrameY := frames[frameIndex-1]
 	frameTime := frames[frameIndex]
 	percent := 1 - (time-frameTime)/(frames[frameIndex-3]-frameTime)
-	percent = t.curve.CurvePercent(frameIndex/3-1, percent)
-
-	bone.X += (bone.Data.x + lastFrameX + (frames[frameIndex+1]-lastFrameX)*percent - bone.X) * alpha
-	bone.Y += (bone.Data.y + lastFrameY + (frames[frameIndex+2]-lastFrameY)*percent - bone.Y) * alpha
+	var percents [2]float32
+	t.curve.percents(frameIndex/3-1, percent, percents[:])
+	return lastFrameX + (frames[frameIndex+1]-lastFrameX)*percents[0], lastFrameY + (frames[frameIndex+2]-lastFrameY)*percents[1]
 }
 
 type ScaleTimeline struct {
@@ -156,10 +245,24 @@ func NewScaleTimeline(l int) *ScaleTimeline {
 	return timeline
 }
 
-func (t *ScaleTimeline) frameCount() int {
+func (t *ScaleTimeline) FrameCount() int {
 	return len(t.frames) / 3
 }
 
+func (t *ScaleTimeline) BoneIndex() int {
+	return t.boneIndex
+}
+
+// Frame returns the time and scale of the frame at index.
+func (t *ScaleTimeline) Frame(index int) (time, x, y float32) {
+	return t.frames[index*3], t.frames[index*3+1], t.frames[index*3+2]
+}
+
+// Curve returns a copy of the timeline's curve.
+func (t *ScaleTimeline) Curve() *Curve {
+	return t.curve.clone()
+}
+
 func (t *ScaleTimeline) setFrame(index int, time, x, y float32) {
 	frameIndex := index * 3
 	t.frames[frameIndex] = time
@@ -168,17 +271,43 @@ func (t *ScaleTimeline) setFrame(index int, time, x, y float32) {
 }
 
 func (t *ScaleTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
-	frames := t.frames
-	if time < frames[0] {
+	t.mix(skeleton, time, alpha, MixReplace)
+}
+
+func (t *ScaleTimeline) mix(skeleton *Skeleton, time, alpha float32, blend MixBlend) {
+	if time < t.frames[0] {
 		return
 	}
 
 	bone := skeleton.Bones[t.boneIndex]
+	if !bone.active {
+		return
+	}
+	x, y := t.valueAt(time)
+	switch blend {
+	case MixAdd:
+		bone.ScaleX *= 1 + (x-1)*alpha
+		bone.ScaleY *= 1 + (y-1)*alpha
+		return
+	case mixSum:
+		bone.ScaleX += (x - 1) * alpha
+		bone.ScaleY += (y - 1) * alpha
+		return
+	}
+	bone.ScaleX += (bone.Data.scaleX - 1 + x - bone.ScaleX) * alpha
+	bone.ScaleY += (bone.Data.scaleY - 1 + y - bone.ScaleY) * alpha
+}
 
+// valueAt returns the scale of the timeline at time, which multiplies the
+// setup pose scale. Times outside the frames take the value of the nearest
+// frame.
+func (t *ScaleTimeline) valueAt(time float32) (x, y float32) {
+	frames := t.frames
+	if time < frames[0] {
+		return frames[1], frames[2]
+	}
 	if time >= frames[len(frames)-3] {
-		bone.ScaleX += (bone.Data.scaleX - 1 + frames[len(frames)-2] - bone.ScaleX) * alpha
-		bone.ScaleY += (bone.Data.scaleY - 1 + frames[len(frames)-1] - bone.ScaleY) * alpha
-		return
+		return frames[len(frames)-2], frames[len(frames)-1]
 	}
 
 	frameIndex := binarySearch(frames, time, 3)
@@ -186,10 +315,9 @@ func (t *ScaleTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
 	lastFrameY := frames[frameIndex-1]
 	frameTime := frames[frameIndex]
 	percent := 1 - (time-frameTime)/(frames[frameIndex-3]-frameTime)
-	percent = t.curve.CurvePercent(frameIndex/3-1, percent)
-
-	bone.ScaleX += (bone.Data.scaleX - 1 + lastFrameX + (frames[frameIndex+1]-lastFrameX)*percent - bone.ScaleX) * alpha
-	bone.ScaleY += (bone.Data.scaleY - 1 + lastFrameY + (frames[frameIndex+2]-lastFrameY)*percent - bone.ScaleY) * alpha
+	var percents [2]float32
+	t.curve.percents(frameIndex/3-1, percent, percents[:])
+	return lastFrameX + (frames[frameIndex+1]-lastFrameX)*percents[0], lastFrameY + (frames[frameIndex+2]-lastFrameY)*percents[1]
 }
 
 type ColorTimeline struct {
@@ -205,8 +333,23 @@ func NewColorTimeline(l int) *ColorTimeline {
 	}
 }
 
-func (t *ColorTimeline) frameCount() int {
-	return t.curve.frameCount()
+func (t *ColorTimeline) FrameCount() int {
+	return t.curve.FrameCount()
+}
+
+func (t *ColorTimeline) SlotIndex() int {
+	return t.slotIndex
+}
+
+// Frame returns the time and color of the frame at index.
+func (t *ColorTimeline) Frame(index int) (time, r, g, b, a float32) {
+	f := t.frames[index*5 : index*5+5]
+	return f[0], f[1], f[2], f[3], f[4]
+}
+
+// Curve returns a copy of the timeline's curve.
+func (t *ColorTimeline) Curve() *Curve {
+	return t.curve.clone()
 }
 
 func (t *ColorTimeline) setFrame(index int, time, r, g, b, a float32) {
@@ -220,20 +363,50 @@ func (t *ColorTimeline) setFrame(index int, time, r, g, b, a float32) {
 }
 
 func (t *ColorTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
-	frames := t.frames
-	if time < frames[0] {
+	t.mix(skeleton, time, alpha, MixReplace)
+}
+
+func (t *ColorTimeline) mix(skeleton *Skeleton, time, alpha float32, blend MixBlend) {
+	if time < t.frames[0] {
 		return // Time is before first frame.
 	}
 
 	slot := skeleton.Slots[t.slotIndex]
+	if !slot.Bone.active {
+		return
+	}
+
+	r, g, b, a := t.valueAt(time)
+	switch {
+	case blend != MixReplace:
+		data := slot.data
+		slot.R = clampColor(slot.R + (r-data.r)*alpha)
+		slot.G = clampColor(slot.G + (g-data.g)*alpha)
+		slot.B = clampColor(slot.B + (b-data.b)*alpha)
+		slot.A = clampColor(slot.A + (a-data.a)*alpha)
+	case alpha < 1:
+		slot.R += (r - slot.R) * alpha
+		slot.G += (g - slot.G) * alpha
+		slot.B += (b - slot.B) * alpha
+		slot.A += (a - slot.A) * alpha
+	default:
+		slot.R = r
+		slot.G = g
+		slot.B = b
+		slot.A = a
+	}
+}
 
-	if time >= frames[len(t.frames)-5] { // Time is after last frame.
+// valueAt returns the color of the timeline at time. Times outside the
+// frames take the value of the nearest frame.
+func (t *ColorTimeline) valueAt(time float32) (r, g, b, a float32) {
+	frames := t.frames
+	if time < frames[0] {
+		return frames[1], frames[2], frames[3], frames[4]
+	}
+	if time >= frames[len(frames)-5] { // Time is after last frame.
 		i := len(frames) - 1
-		slot.R = frames[i-3]
-		slot.G = frames[i-2]
-		slot.B = frames[i-1]
-		slot.A = frames[i]
-		return
+		return frames[i-3], frames[i-2], frames[i-1], frames[i]
 	}
 
 	// Interpolate between the last frame and the current frame.
@@ -244,29 +417,40 @@ func (t *ColorTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
 	lastFrameA := frames[frameIndex-1]
 	frameTime := frames[frameIndex]
 	percent := 1 - (time-frameTime)/(frames[frameIndex-5]-frameTime)
-	percent = t.curve.CurvePercent(frameIndex/5-1, percent)
+	var percents [4]float32
+	t.curve.percents(frameIndex/5-1, percent, percents[:])
 
-	r := lastFrameR + (frames[frameIndex+1]-lastFrameR)*percent
-	g := lastFrameG + (frames[frameIndex+2]-lastFrameG)*percent
-	b := lastFrameB + (frames[frameIndex+3]-lastFrameB)*percent
-	a := lastFrameA + (frames[frameIndex+4]-lastFrameA)*percent
-	if alpha < 1 {
-		slot.R += (r - slot.R) * alpha
-		slot.G += (g - slot.G) * alpha
-		slot.B += (b - slot.B) * alpha
-		slot.A += (a - slot.A) * alpha
-	} else {
-		slot.R = r
-		slot.G = g
-		slot.B = b
-		slot.A = a
+	r = lastFrameR + (frames[frameIndex+1]-lastFrameR)*percents[0]
+	g = lastFrameG + (frames[frameIndex+2]-lastFrameG)*percents[1]
+	b = lastFrameB + (frames[frameIndex+3]-lastFrameB)*percents[2]
+	a = lastFrameA + (frames[frameIndex+4]-lastFrameA)*percents[3]
+	return
+}
+
+func clampColor(value float32) float32 {
+	if value < 0 {
+		return 0
 	}
+	if value > 1 {
+		return 1
+	}
+	return value
 }
 
 type AttachmentTimeline struct {
 	slotIndex       int
 	frames          []float32
 	attachmentNames []string
+	resolved        map[*Skin]*resolvedAttachments
+}
+
+// resolvedAttachments holds the attachment for every frame as seen through
+// one skin, valid while neither that skin nor the default skin changes.
+type resolvedAttachments struct {
+	defaultSkin     *Skin
+	skinRevision    int
+	defaultRevision int
+	attachments     []Attachment
 }
 
 func NewAttachmentTimeline(l int) *AttachmentTimeline {
@@ -276,10 +460,20 @@ func NewAttachmentTimeline(l int) *AttachmentTimeline {
 	}
 }
 
-func (t *AttachmentTimeline) frameCount() int {
+func (t *AttachmentTimeline) FrameCount() int {
 	return len(t.frames)
 }
 
+func (t *AttachmentTimeline) SlotIndex() int {
+	return t.slotIndex
+}
+
+// Frame returns the time and attachment name of the frame at index. The
+// name is empty when the frame clears the attachment.
+func (t *AttachmentTimeline) Frame(index int) (time float32, attachmentName string) {
+	return t.frames[index], t.attachmentNames[index]
+}
+
 func (t *AttachmentTimeline) setFrame(index int, time float32, attachmentName string) {
 	t.frames[index] = time
 	t.attachmentNames[index] = attachmentName
@@ -291,6 +485,11 @@ func (t *AttachmentTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
 		return // Time is before first frame.
 	}
 
+	slot := skeleton.Slots[t.slotIndex]
+	if !slot.Bone.active {
+		return
+	}
+
 	var frameIndex int
 	if time >= frames[len(frames)-1] { // Time is after last frame.
 		frameIndex = len(frames) - 1
@@ -298,12 +497,76 @@ func (t *AttachmentTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
 		frameIndex = binarySearch(frames, time, 1) - 1
 	}
 
-	attachmentName := t.attachmentNames[frameIndex]
+	slot.Attachment = t.attachment(skeleton, frameIndex)
+	slot.nameAttachment(slot.Attachment, t.attachmentNames[frameIndex])
+}
+
+// setToSetupPose shows the slot's setup pose attachment, for when time runs
+// back to before the first frame.
+func (t *AttachmentTimeline) setToSetupPose(skeleton *Skeleton) {
+	slot := skeleton.Slots[t.slotIndex]
+	if !slot.Bone.active {
+		return
+	}
 	var attachment Attachment
-	if attachmentName != "" {
-		attachment = skeleton.AttachmentBySlotIndex(t.slotIndex, attachmentName)
+	name := slot.data.attachmentName
+	if name != "" {
+		attachment = skeleton.AttachmentBySlotIndex(t.slotIndex, name)
+	}
+	slot.Attachment = attachment
+	slot.nameAttachment(attachment, name)
+}
+
+func (t *AttachmentTimeline) attachment(skeleton *Skeleton, frameIndex int) Attachment {
+	attachmentName := t.attachmentNames[frameIndex]
+	if attachmentName == "" {
+		return nil
+	}
+	if r, ok := t.resolved[skeleton.skin]; ok && r.valid(skeleton.skin, skeleton.data.defaultSkin) {
+		return r.attachments[frameIndex]
+	}
+	return skeleton.AttachmentBySlotIndex(t.slotIndex, attachmentName)
+}
+
+// resolve looks up the attachment of every frame for each skin in data, so
+// that Apply does not need to go through the skins by name.
+func (t *AttachmentTimeline) resolve(data *SkeletonData) {
+	t.resolved = make(map[*Skin]*resolvedAttachments)
+	skins := append([]*Skin{nil}, data.skins...)
+	for _, skin := range skins {
+		r := &resolvedAttachments{
+			defaultSkin: data.defaultSkin,
+			attachments: make([]Attachment, len(t.frames)),
+		}
+		if skin != nil {
+			r.skinRevision = skin.revision
+		}
+		if data.defaultSkin != nil {
+			r.defaultRevision = data.defaultSkin.revision
+		}
+		for i, name := range t.attachmentNames {
+			if name == "" {
+				continue
+			}
+			if skin != nil {
+				r.attachments[i] = skin.Attachment(t.slotIndex, name)
+			}
+			if r.attachments[i] == nil && data.defaultSkin != nil {
+				r.attachments[i] = data.defaultSkin.Attachment(t.slotIndex, name)
+			}
+		}
+		t.resolved[skin] = r
 	}
-	skeleton.Slots[t.slotIndex].Attachment = attachment
+}
+
+func (r *resolvedAttachments) valid(skin, defaultSkin *Skin) bool {
+	if r.defaultSkin != defaultSkin {
+		return false
+	}
+	if skin != nil && skin.revision != r.skinRevision {
+		return false
+	}
+	return defaultSkin == nil || defaultSkin.revision == r.defaultRevision
 }
 
 type Animation struct {
@@ -338,6 +601,95 @@ func (a *Animation) Mix(skeleton *Skeleton, time float32, loop bool, alpha float
 	}
 }
 
+// MixOptions controls how MixWith applies an animation.
+type MixOptions struct {
+	// Mask limits the bones and slots the animation affects. A nil mask
+	// affects all of them.
+	Mask *Mask
+	// Blend selects how the animation combines with the current pose.
+	Blend MixBlend
+}
+
+// MixWith is like Mix, with options such as a mask for layering animations
+// that drive different parts of the skeleton, or additive blending for
+// animations like breathing that play on top of others.
+func (a *Animation) MixWith(skeleton *Skeleton, time float32, loop bool, alpha float32, options MixOptions) {
+	if loop && a.duration != 0 {
+		time = float32(math.Mod(float64(time), float64(a.duration)))
+	}
+	for _, timeline := range a.timelines {
+		if !options.Mask.allows(timeline) {
+			continue
+		}
+		if t, ok := timeline.(blendTimeline); ok {
+			t.mix(skeleton, time, alpha, options.Blend)
+		} else {
+			timeline.Apply(skeleton, time, alpha)
+		}
+	}
+}
+
+// SetExactCurves selects exact or approximate evaluation of the Bezier
+// curves of every timeline. See Curve.SetExact.
+func (a *Animation) SetExactCurves(exact bool) {
+	for _, timeline := range a.timelines {
+		switch t := timeline.(type) {
+		case *RotateTimeline:
+			t.curve.SetExact(exact)
+		case *TranslateTimeline:
+			t.curve.SetExact(exact)
+		case *ScaleTimeline:
+			t.curve.SetExact(exact)
+		case *ColorTimeline:
+			t.curve.SetExact(exact)
+		}
+	}
+}
+
+// Timelines returns the timelines in the order they were loaded.
+func (a *Animation) Timelines() []Timeline {
+	return append([]Timeline(nil), a.timelines...)
+}
+
+func (a *Animation) Name() string {
+	return a.name
+}
+
 func (a *Animation) Duration() float32 {
 	return a.duration
 }
+
+// Bounds samples the animation fps times per second over its whole duration
+// and returns the union of the skeleton bounds at every sample. The skeleton
+// is left posed at the end of the animation.
+func (a *Animation) Bounds(skeleton *Skeleton, fps float32) (minX, minY, maxX, maxY float32) {
+	if fps <= 0 {
+		fps = 30
+	}
+	step := 1 / fps
+	first := true
+	for time := float32(0); ; time += step {
+		if time > a.duration {
+			time = a.duration
+		}
+		skeleton.SetToSetupPose()
+		a.Apply(skeleton, time, false)
+		skeleton.UpdateWorldTransform()
+		x1, y1, x2, y2, ok := skeleton.bounds()
+		if ok {
+			if first {
+				minX, minY, maxX, maxY = x1, y1, x2, y2
+				first = false
+			} else {
+				minX = float32(math.Min(float64(minX), float64(x1)))
+				minY = float32(math.Min(float64(minY), float64(y1)))
+				maxX = float32(math.Max(float64(maxX), float64(x2)))
+				maxY = float32(math.Max(float64(maxY), float64(y2)))
+			}
+		}
+		if time >= a.duration {
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package spine
+
+import "testing"
+
+func TestCurveIsCopy(t *testing.T) {
+	data := loadTest(t)
+	_, walk := data.FindAnimation("walk")
+	for _, timeline := range walk.Timelines() {
+		rotate, ok := timeline.(*RotateTimeline)
+		if !ok {
+			continue
+		}
+		before := rotate.valueAt(0.25)
+		rotate.Curve().SetStepped(0)
+		if after := rotate.valueAt(0.25); after != before {
+			t.Fatalf("changing the returned curve changed the timeline: %v to %v", before, after)
+		}
+	}
+}
+
+func BenchmarkAttachmentTimelineApply(b *testing.B) {
+	skeleton := NewSkeleton(loadTest(b))
+	_, walk := skeleton.Data().FindAnimation("walk")
+	var timeline *AttachmentTimeline
+	for _, t := range walk.Timelines() {
+		if t, ok := t.(*AttachmentTimeline); ok {
+			timeline = t
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timeline.Apply(skeleton, float32(i%2)*0.5, 1)
+	}
+}
+
+// frameTimes switch the head attachment off and on between frames.
+var frameTimes = [...]float32{0.1, 0.6, 0.3, 0.9}
+
+func BenchmarkAnimatedFrame(b *testing.B) {
+	skeleton := NewSkeleton(loadTest(b))
+	skeleton.SetSkinByName("fancy")
+	walk := skeleton.FindAnimation("walk")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		walk.Apply(skeleton, frameTimes[i%len(frameTimes)], true)
+		skeleton.UpdateWorldTransform()
+	}
+}
+
+func TestAnimatedFrameAllocs(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skeleton.SetSkinByName("fancy")
+	walk := skeleton.FindAnimation("walk")
+	i := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		walk.Apply(skeleton, frameTimes[i%len(frameTimes)], true)
+		skeleton.UpdateWorldTransform()
+		i++
+	})
+	if allocs != 0 {
+		t.Fatalf("an animated frame allocated %v times", allocs)
+	}
+}
+
+func TestMixAdd(t *testing.T) {
+	data := loadTest(t)
+	b := NewAnimationBuilder("recoil", data)
+	b.Rotate("hip", 0, 390, CurveLinear)
+	b.Translate("root", 0, 10, 5, CurveLinear)
+	b.Scale("hip", 0, 2, 1, CurveLinear)
+	b.Color("head", 0, 1, 0.25, 0, 0.9, CurveLinear)
+	recoil, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	skeleton := NewSkeleton(data)
+	walk := skeleton.FindAnimation("walk")
+	root, hip, head := skeleton.Bones[0], skeleton.Bones[1], skeleton.Slots[1]
+
+	// Halfway through walk the root is at 50 turned 5, the hip is turned 20
+	// and scaled 1.25, and the head is half faded to ff000080.
+	walk.Apply(skeleton, 0.5, false)
+	recoil.MixWith(skeleton, 0, false, 0.5, MixOptions{Blend: MixAdd})
+	tests := []struct {
+		name      string
+		got, want float32
+	}{
+		{"root x", root.X, 50 + 10*0.5},
+		{"root y", root.Y, 5 * 0.5},
+		{"root rotation", root.Rotation, 5},
+		{"hip rotation", hip.Rotation, 20 + 30*0.5},
+		{"hip scale x", hip.ScaleX, 1.25 * 1.5},
+		{"hip scale y", hip.ScaleY, 1},
+		{"head red", head.R, 1},
+		{"head green", head.G, 0.5 + 0.25*0.5},
+		{"head alpha", head.A, (1+128.0/255)/2 - 0.1*0.5},
+	}
+	for _, test := range tests {
+		if !near(test.got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+
+	skeleton.SetToSetupPose()
+	recoil.MixWith(skeleton, 0, false, 1, MixOptions{Blend: MixAdd})
+	recoil.MixWith(skeleton, 0, false, 1, MixOptions{Blend: MixAdd})
+	if !near(root.X, 20) || !near(hip.Rotation, 60) || !near(hip.ScaleX, 4) {
+		t.Errorf("adding twice: got root x %v, hip rotation %v and scale %v, want 20, 60 and 4", root.X, hip.Rotation, hip.ScaleX)
+	}
+}
@@ -12,6 +12,7 @@ type Atlas struct {
 	Pages   []*AtlasPage
 	Regions []*AtlasRegion
 	loader  TextureLoader
+	regions map[string]*AtlasRegion
 }
 
 func NewAtlas(r io.Reader, loader TextureLoader) (*Atlas, error) {
@@ -20,6 +21,7 @@ func NewAtlas(r io.Reader, loader TextureLoader) (*Atlas, error) {
 	}
 	var atlas Atlas
 	atlas.loader = loader
+	atlas.regions = make(map[string]*AtlasRegion)
 
 	scanner := bufio.NewScanner(r)
 	var page *AtlasPage
@@ -214,6 +216,9 @@ func NewAtlas(r io.Reader, loader TextureLoader) (*Atlas, error) {
 			region.Index = regIdx
 
 			atlas.Regions = append(atlas.Regions, region)
+			if _, ok := atlas.regions[region.Name]; !ok {
+				atlas.regions[region.Name] = region
+			}
 		}
 	}
 	if scanner.Err() != nil {
@@ -262,10 +267,13 @@ func readTuple(expectedKey string, s *bufio.Scanner) ([]string, error) {
 	return tuple, nil
 }
 
-/** Returns the first region found with the specified name. This method uses string comparison to find the region, so the result
-* should be cached rather than calling this method multiple times.
+/** Returns the first region found with the specified name. Regions read by NewAtlas are found through an index; regions
+* added to Regions afterwards fall back to a string comparison.
 * @return The region, or null. */
 func (a *Atlas) FindRegion(name string) *AtlasRegion {
+	if region, ok := a.regions[name]; ok {
+		return region
+	}
 	for _, region := range a.Regions {
 		if region.Name == name {
 			return region
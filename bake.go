@@ -0,0 +1,207 @@
+package spine
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Bake returns a copy of the animation that needs no curve evaluation. Each
+// rotate, translate, scale and color timeline is sampled fps times a second,
+// and at its own keys, into linear keys, and keys that interpolating
+// between the keys kept reproduces within tolerance are dropped. Keys that
+// are stepped in every channel stay stepped. Other timelines are shared with
+// the original.
+func Bake(animation *Animation, fps, tolerance float32) (*Animation, error) {
+	if fps <= 0 {
+		return nil, errors.New("spine: bake fps must be positive")
+	}
+	if tolerance < 0 {
+		return nil, errors.New("spine: bake tolerance must not be negative")
+	}
+	timelines := make([]Timeline, len(animation.timelines))
+	for i, timeline := range animation.timelines {
+		timelines[i] = bakeTimeline(timeline, fps, tolerance)
+	}
+	return NewAnimation(animation.name, timelines, animation.duration), nil
+}
+
+func bakeTimeline(timeline Timeline, fps, tolerance float32) Timeline {
+	switch t := timeline.(type) {
+	case *RotateTimeline:
+		// Angles are sampled unwrapped so that keys can be dropped across
+		// a turn.
+		s := sampleTimeline(t.frames, 2, t.curve, fps, 1, func(time float32, v []float32) {
+			v[0] = t.totalAngle(time)
+		})
+		// Rotations take the shorter way between keys, so no span may turn
+		// more than half way round.
+		s.maxDelta = 180
+		keys := s.reduce(tolerance)
+		baked := NewRotateTimeline(len(keys))
+		baked.boneIndex = t.boneIndex
+		for i, k := range keys {
+			baked.setFrame(i, s.times[k], s.values[k])
+		}
+		s.setCurves(baked.curve, keys)
+		return baked
+	case *TranslateTimeline:
+		s := sampleTimeline(t.frames, 3, t.curve, fps, 2, func(time float32, v []float32) {
+			v[0], v[1] = t.valueAt(time)
+		})
+		keys := s.reduce(tolerance)
+		baked := NewTranslateTimeline(len(keys))
+		baked.boneIndex = t.boneIndex
+		for i, k := range keys {
+			baked.setFrame(i, s.times[k], s.values[k*2], s.values[k*2+1])
+		}
+		s.setCurves(baked.curve, keys)
+		return baked
+	case *ScaleTimeline:
+		s := sampleTimeline(t.frames, 3, t.curve, fps, 2, func(time float32, v []float32) {
+			v[0], v[1] = t.valueAt(time)
+		})
+		keys := s.reduce(tolerance)
+		baked := NewScaleTimeline(len(keys))
+		baked.boneIndex = t.boneIndex
+		for i, k := range keys {
+			baked.setFrame(i, s.times[k], s.values[k*2], s.values[k*2+1])
+		}
+		s.setCurves(baked.curve, keys)
+		return baked
+	case *ColorTimeline:
+		s := sampleTimeline(t.frames, 5, t.curve, fps, 4, func(time float32, v []float32) {
+			v[0], v[1], v[2], v[3] = t.valueAt(time)
+		})
+		keys := s.reduce(tolerance)
+		baked := NewColorTimeline(len(keys))
+		baked.slotIndex = t.slotIndex
+		for i, k := range keys {
+			v := s.values[k*4 : k*4+4]
+			baked.setFrame(i, s.times[k], v[0], v[1], v[2], v[3])
+		}
+		s.setCurves(baked.curve, keys)
+		return baked
+	}
+	return timeline
+}
+
+// samples holds the values of a timeline's channels at a series of times.
+type samples struct {
+	channels int
+	times    []float32
+	values   []float32
+	// stepped is true for each span between consecutive times that holds
+	// the value of the earlier time.
+	stepped []bool
+	// maxDelta limits how much a channel may change over a span, if not 0.
+	maxDelta float32
+}
+
+// sampleTimeline samples a timeline with the given frame stride from its
+// first key to its last, fps times a second and at every key.
+func sampleTimeline(frames []float32, stride int, curve *Curve, fps float32, channels int, value func(time float32, values []float32)) *samples {
+	first, last := frames[0], frames[len(frames)-stride]
+	var times []float32
+	for i := 0; ; i++ {
+		time := first + float32(i)/fps
+		if time >= last {
+			break
+		}
+		times = append(times, time)
+	}
+	for i := 0; i < len(frames); i += stride {
+		times = append(times, frames[i])
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	s := &samples{channels: channels}
+	for _, time := range times {
+		if n := len(s.times); n > 0 && time-s.times[n-1] < 1e-6 {
+			continue
+		}
+		s.times = append(s.times, time)
+	}
+
+	s.values = make([]float32, len(s.times)*channels)
+	for i, time := range s.times {
+		value(time, s.values[i*channels:(i+1)*channels])
+	}
+
+	s.stepped = make([]bool, len(s.times))
+	frame := 0
+	for i, time := range s.times[:len(s.times)-1] {
+		for (frame+2)*stride <= len(frames) && frames[(frame+1)*stride] <= time {
+			frame++
+		}
+		s.stepped[i] = frame < curve.FrameCount()-1 && curve.isStepped(frame)
+	}
+	return s
+}
+
+// reduce returns the indices of the samples to keep so that linear
+// interpolation between them is within tolerance of every sample. Stepped
+// spans keep their ends and every change of value.
+func (s *samples) reduce(tolerance float32) []int {
+	n := len(s.times)
+	keys := []int{0}
+	for i := 1; i < n-1; i++ {
+		last := keys[len(keys)-1]
+		switch {
+		case s.stepped[i-1] && s.stepped[i]:
+			// Inside a stepped span only changes of value matter.
+			if !s.equal(last, i) {
+				keys = append(keys, i)
+			}
+		case s.stepped[i-1] || s.stepped[i] || !s.fitsLinear(last, i+1, tolerance):
+			keys = append(keys, i)
+		}
+	}
+	if n > 1 {
+		keys = append(keys, n-1)
+	}
+	return keys
+}
+
+func (s *samples) equal(a, b int) bool {
+	for c := 0; c < s.channels; c++ {
+		if s.values[a*s.channels+c] != s.values[b*s.channels+c] {
+			return false
+		}
+	}
+	return true
+}
+
+// fitsLinear reports whether interpolating linearly between samples a and b
+// reproduces every sample between them within tolerance, without changing
+// by more than maxDelta.
+func (s *samples) fitsLinear(a, b int, tolerance float32) bool {
+	if s.maxDelta > 0 {
+		for c := 0; c < s.channels; c++ {
+			if math.Abs(float64(s.values[b*s.channels+c]-s.values[a*s.channels+c])) > float64(s.maxDelta) {
+				return false
+			}
+		}
+	}
+	duration := s.times[b] - s.times[a]
+	for i := a + 1; i < b; i++ {
+		percent := (s.times[i] - s.times[a]) / duration
+		for c := 0; c < s.channels; c++ {
+			v1, v2 := s.values[a*s.channels+c], s.values[b*s.channels+c]
+			if math.Abs(float64(v1+(v2-v1)*percent-s.values[i*s.channels+c])) > float64(tolerance) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// setCurves makes the curve between the kept samples stepped where the
+// samples were.
+func (s *samples) setCurves(curve *Curve, keys []int) {
+	for i, k := range keys[:len(keys)-1] {
+		if s.stepped[k] {
+			curve.SetStepped(i)
+		}
+	}
+}
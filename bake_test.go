@@ -0,0 +1,54 @@
+package spine
+
+import "testing"
+
+func TestBakeKeepsRotationsUnderHalfTurn(t *testing.T) {
+	original := NewRotateTimeline(5)
+	for i := 0; i < 5; i++ {
+		original.setFrame(i, float32(i), float32(i)*90)
+	}
+	animation := NewAnimation("spin", []Timeline{original}, 4)
+	baked, err := Bake(animation, 4, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotate := baked.timelines[0].(*RotateTimeline)
+	tests := []struct {
+		time, angle float32
+	}{
+		{0.5, 45},
+		{1.5, 135},
+		{2.5, -135},
+		{3.5, -45},
+	}
+	for _, test := range tests {
+		if got := wrapAngle(rotate.valueAt(test.time)); !near(got, test.angle) {
+			t.Errorf("at %v: got %v, want %v", test.time, got, test.angle)
+		}
+	}
+}
+
+func TestBakeDropsLinearKeys(t *testing.T) {
+	_, walk := loadTest(t).FindAnimation("walk")
+	baked, err := Bake(walk, 30, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, timeline := range baked.timelines {
+		translate, ok := timeline.(*TranslateTimeline)
+		if !ok {
+			continue
+		}
+		if n := translate.curve.FrameCount(); n != 2 {
+			t.Errorf("got %d keys for a linear translation, want 2", n)
+		}
+		x, _ := translate.valueAt(0.3)
+		wantX, _ := walk.timelines[i].(*TranslateTimeline).valueAt(0.3)
+		if !near(x, wantX) {
+			t.Errorf("got x %v, want %v", x, wantX)
+		}
+	}
+	if _, err := Bake(walk, 0, 0); err == nil {
+		t.Error("baked at 0 fps")
+	}
+}
@@ -0,0 +1,387 @@
+package spine
+
+import (
+	"errors"
+	"math"
+)
+
+// blendSpace holds what 1D and 2D blend spaces share: the animations, their
+// current weights and the normalized time that keeps them in step.
+type blendSpace struct {
+	animations []*Animation
+	weights    []float32
+	order      []int
+	phase      float32
+
+	// Scratch poses for mixing.
+	from, to Pose
+}
+
+func (b *blendSpace) add(animation *Animation) {
+	b.animations = append(b.animations, animation)
+	b.weights = append(b.weights, 0)
+	b.order = append(b.order, len(b.order))
+}
+
+// Animations returns the animations in the order they were added.
+func (b *blendSpace) Animations() []*Animation {
+	return append([]*Animation(nil), b.animations...)
+}
+
+// Weights returns the weight of each animation, in the order they were
+// added. The weights add up to 1 once an animation has been added.
+func (b *blendSpace) Weights() []float32 {
+	return append([]float32(nil), b.weights...)
+}
+
+// Phase returns the normalized time shared by all animations, from 0 to 1.
+func (b *blendSpace) Phase() float32 {
+	return b.phase
+}
+
+func (b *blendSpace) SetPhase(phase float32) {
+	b.phase = phase - float32(math.Floor(float64(phase)))
+}
+
+// Duration returns the length of one cycle at the current weights: the
+// weighted average of the animation durations.
+func (b *blendSpace) Duration() float32 {
+	var duration float32
+	for i, animation := range b.animations {
+		duration += animation.duration * b.weights[i]
+	}
+	return duration
+}
+
+// Update advances the phase by dt seconds. Every animation covers the same
+// fraction of its own duration, so that blended cycles such as walk and run
+// keep their feet in step.
+func (b *blendSpace) Update(dt float32) {
+	if duration := b.Duration(); duration > 0 {
+		b.SetPhase(b.phase + dt/duration)
+	}
+}
+
+func (b *blendSpace) Mix(skeleton *Skeleton, alpha float32) error {
+	return b.MixWith(skeleton, alpha, MixOptions{})
+}
+
+// MixWith mixes the weighted sum of the animations at the current phase into
+// the skeleton by alpha. Bones and slots an animation does not key count as
+// being in the setup pose for that animation's share. An error means that
+// the pose could not be blended or an attachment of the blended pose could
+// not be shown, and the skeleton is left partly posed.
+func (b *blendSpace) MixWith(skeleton *Skeleton, alpha float32, options MixOptions) error {
+	// Apply the heaviest animation last so that its attachments win.
+	order := b.order
+	for i := 1; i < len(order); i++ {
+		for j := i; j > 0 && b.weights[order[j]] < b.weights[order[j-1]]; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+
+	if options.Blend == MixAdd {
+		for _, i := range order {
+			b.mixAnimation(skeleton, i, alpha*b.weights[i], options)
+		}
+		return nil
+	}
+
+	// The weighted sum is built by adding each animation's offset from the
+	// setup pose, then the current pose is moved towards it.
+	b.from.Capture(skeleton)
+	for i, bone := range skeleton.Bones {
+		if options.Mask == nil || options.Mask.HasBone(i) {
+			bone.SetToSetupPose()
+		}
+	}
+	for i, slot := range skeleton.Slots {
+		if options.Mask == nil || options.Mask.HasSlot(i) {
+			data := slot.data
+			slot.R, slot.G, slot.B, slot.A = data.r, data.g, data.b, data.a
+		}
+	}
+	addOptions := options
+	addOptions.Blend = mixSum
+	for _, i := range order {
+		b.mixAnimation(skeleton, i, b.weights[i], addOptions)
+	}
+	b.to.Capture(skeleton)
+	if err := b.to.Lerp(&b.from, &b.to, alpha); err != nil {
+		return err
+	}
+	return b.to.Restore(skeleton)
+}
+
+func (b *blendSpace) mixAnimation(skeleton *Skeleton, i int, alpha float32, options MixOptions) {
+	if alpha <= 0 {
+		return
+	}
+	animation := b.animations[i]
+	animation.MixWith(skeleton, b.phase*animation.duration, false, alpha, options)
+}
+
+// BlendSpace1D blends animations placed along one parameter, such as idle,
+// walk and run placed by speed. The two animations either side of the value
+// are weighted linearly; values beyond the ends use the nearest animation.
+type BlendSpace1D struct {
+	blendSpace
+	positions []float32
+	value     float32
+}
+
+func NewBlendSpace1D() *BlendSpace1D {
+	return new(BlendSpace1D)
+}
+
+func (b *BlendSpace1D) Add(animation *Animation, position float32) {
+	b.add(animation)
+	b.positions = append(b.positions, position)
+	b.SetValue(b.value)
+}
+
+func (b *BlendSpace1D) Value() float32 {
+	return b.value
+}
+
+func (b *BlendSpace1D) SetValue(value float32) {
+	b.value = value
+	lower, upper := -1, -1
+	for i, position := range b.positions {
+		b.weights[i] = 0
+		if position <= value && (lower == -1 || position > b.positions[lower]) {
+			lower = i
+		}
+		if position >= value && (upper == -1 || position < b.positions[upper]) {
+			upper = i
+		}
+	}
+	switch {
+	case lower == -1 && upper == -1:
+	case lower == -1:
+		b.weights[upper] = 1
+	case upper == -1 || b.positions[lower] == b.positions[upper]:
+		b.weights[lower] = 1
+	default:
+		t := (value - b.positions[lower]) / (b.positions[upper] - b.positions[lower])
+		b.weights[lower] = 1 - t
+		b.weights[upper] = t
+	}
+}
+
+// BlendSpace2D blends animations placed at points on a plane, such as
+// strafing animations placed by direction. The points are triangulated and
+// the animations at the corners of the triangle holding the value are
+// weighted by its barycentric coordinates. Values outside every triangle
+// use the nearest point on the outer edges.
+type BlendSpace2D struct {
+	blendSpace
+	xs, ys    []float32
+	triangles [][3]int
+	edges     [][2]int
+	x, y      float32
+}
+
+func NewBlendSpace2D() *BlendSpace2D {
+	return new(BlendSpace2D)
+}
+
+// Add places the animation at x, y. Each point can hold one animation.
+func (b *BlendSpace2D) Add(animation *Animation, x, y float32) error {
+	for i := range b.xs {
+		if b.xs[i] == x && b.ys[i] == y {
+			return errors.New("spine: blend space already has an animation at this point")
+		}
+	}
+	b.add(animation)
+	b.xs = append(b.xs, x)
+	b.ys = append(b.ys, y)
+	b.triangulate()
+	b.SetValue(b.x, b.y)
+	return nil
+}
+
+func (b *BlendSpace2D) Value() (x, y float32) {
+	return b.x, b.y
+}
+
+func (b *BlendSpace2D) SetValue(x, y float32) {
+	b.x, b.y = x, y
+	for i := range b.weights {
+		b.weights[i] = 0
+	}
+	if len(b.xs) == 0 {
+		return
+	}
+
+	for _, t := range b.triangles {
+		w0, w1, w2, ok := b.barycentric(t, x, y)
+		if ok {
+			b.weights[t[0]], b.weights[t[1]], b.weights[t[2]] = w0, w1, w2
+			return
+		}
+	}
+
+	if len(b.edges) == 0 {
+		b.weights[0] = 1
+		return
+	}
+	best := float32(math.Inf(1))
+	var bestEdge [2]int
+	var bestT float32
+	for _, e := range b.edges {
+		t, distance := b.project(e, x, y)
+		if distance < best {
+			best, bestEdge, bestT = distance, e, t
+		}
+	}
+	b.weights[bestEdge[0]] = 1 - bestT
+	b.weights[bestEdge[1]] = bestT
+}
+
+const blendEpsilon = 1e-5
+
+// barycentric returns the weights of the triangle's corners at x, y, and
+// whether the point is inside the triangle.
+func (b *BlendSpace2D) barycentric(t [3]int, x, y float32) (w0, w1, w2 float32, ok bool) {
+	x0, y0 := b.xs[t[0]], b.ys[t[0]]
+	x1, y1 := b.xs[t[1]], b.ys[t[1]]
+	x2, y2 := b.xs[t[2]], b.ys[t[2]]
+	d := (y1-y2)*(x0-x2) + (x2-x1)*(y0-y2)
+	w0 = ((y1-y2)*(x-x2) + (x2-x1)*(y-y2)) / d
+	w1 = ((y2-y0)*(x-x2) + (x0-x2)*(y-y2)) / d
+	w2 = 1 - w0 - w1
+	ok = w0 >= -blendEpsilon && w1 >= -blendEpsilon && w2 >= -blendEpsilon
+	return
+}
+
+// project returns how far along the edge the point nearest to x, y is, from
+// 0 to 1, and the squared distance to it.
+func (b *BlendSpace2D) project(e [2]int, x, y float32) (t, distance float32) {
+	x0, y0 := b.xs[e[0]], b.ys[e[0]]
+	dx, dy := b.xs[e[1]]-x0, b.ys[e[1]]-y0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = ((x-x0)*dx + (y-y0)*dy) / length
+	}
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	px, py := x0+dx*t-x, y0+dy*t-y
+	return t, px*px + py*py
+}
+
+// triangulate computes the Delaunay triangulation of the points and the
+// edges on its outside. Blend spaces have few points, so every triangle is
+// simply tested against every point. When more than three points share a
+// circle, the first triangles that do not overlap are kept.
+func (b *BlendSpace2D) triangulate() {
+	b.triangles = b.triangles[:0]
+	b.edges = b.edges[:0]
+	n := len(b.xs)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			for k := j + 1; k < n; k++ {
+				t := [3]int{i, j, k}
+				if b.isDelaunay(t) && !b.overlaps(t) {
+					b.triangles = append(b.triangles, t)
+				}
+			}
+		}
+	}
+
+	if len(b.triangles) == 0 {
+		// The points are on a line, so blend along it between neighbors.
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if b.isNeighbor(i, j) {
+					b.edges = append(b.edges, [2]int{i, j})
+				}
+			}
+		}
+		return
+	}
+	count := make(map[[2]int]int)
+	for _, t := range b.triangles {
+		for _, e := range triangleEdges(t) {
+			count[e]++
+		}
+	}
+	for _, t := range b.triangles {
+		for _, e := range triangleEdges(t) {
+			if count[e] == 1 {
+				b.edges = append(b.edges, e)
+			}
+		}
+	}
+}
+
+// isNeighbor reports whether no other point lies between the points i and
+// j, which are on a line with all the others.
+func (b *BlendSpace2D) isNeighbor(i, j int) bool {
+	for k := range b.xs {
+		if k == i || k == j {
+			continue
+		}
+		if t, _ := b.project([2]int{i, j}, b.xs[k], b.ys[k]); t > 0 && t < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func triangleEdges(t [3]int) [3][2]int {
+	return [3][2]int{{t[0], t[1]}, {t[1], t[2]}, {t[0], t[2]}}
+}
+
+// isDelaunay reports whether the triangle has area and no other point lies
+// strictly inside its circumcircle.
+func (b *BlendSpace2D) isDelaunay(t [3]int) bool {
+	ax, ay := float64(b.xs[t[0]]), float64(b.ys[t[0]])
+	bx, by := float64(b.xs[t[1]]), float64(b.ys[t[1]])
+	cx, cy := float64(b.xs[t[2]]), float64(b.ys[t[2]])
+	d := 2 * (ax*(by-cy) + bx*(cy-ay) + cx*(ay-by))
+	if math.Abs(d) < blendEpsilon {
+		return false
+	}
+	ux := ((ax*ax+ay*ay)*(by-cy) + (bx*bx+by*by)*(cy-ay) + (cx*cx+cy*cy)*(ay-by)) / d
+	uy := ((ax*ax+ay*ay)*(cx-bx) + (bx*bx+by*by)*(ax-cx) + (cx*cx+cy*cy)*(bx-ax)) / d
+	r := (ax-ux)*(ax-ux) + (ay-uy)*(ay-uy)
+	for i := range b.xs {
+		if i == t[0] || i == t[1] || i == t[2] {
+			continue
+		}
+		px, py := float64(b.xs[i])-ux, float64(b.ys[i])-uy
+		if px*px+py*py < r*(1-blendEpsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps reports whether an edge of the triangle crosses an edge of a
+// triangle already kept.
+func (b *BlendSpace2D) overlaps(t [3]int) bool {
+	for _, other := range b.triangles {
+		for _, e1 := range triangleEdges(t) {
+			for _, e2 := range triangleEdges(other) {
+				if b.crosses(e1, e2) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// crosses reports whether two edges cross at a point inside both.
+func (b *BlendSpace2D) crosses(e1, e2 [2]int) bool {
+	if e1[0] == e2[0] || e1[0] == e2[1] || e1[1] == e2[0] || e1[1] == e2[1] {
+		return false
+	}
+	side := func(e [2]int, p int) float32 {
+		return (b.xs[e[1]]-b.xs[e[0]])*(b.ys[p]-b.ys[e[0]]) - (b.ys[e[1]]-b.ys[e[0]])*(b.xs[p]-b.xs[e[0]])
+	}
+	return side(e1, e2[0])*side(e1, e2[1]) < 0 && side(e2, e1[0])*side(e2, e1[1]) < 0
+}
@@ -0,0 +1,123 @@
+package spine
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestBlendSpace1DWeights(t *testing.T) {
+	b := NewBlendSpace1D()
+	b.Add(NewAnimation("walk", nil, 2), 2)
+	b.Add(NewAnimation("idle", nil, 1), 0)
+	b.Add(NewAnimation("run", nil, 3), 4)
+	tests := []struct {
+		value   float32
+		weights []float32
+	}{
+		{1, []float32{0.5, 0.5, 0}},
+		{3.5, []float32{0.25, 0, 0.75}},
+		{-5, []float32{0, 1, 0}},
+		{5, []float32{0, 0, 1}},
+	}
+	for _, test := range tests {
+		b.SetValue(test.value)
+		for i, w := range b.Weights() {
+			if !near(w, test.weights[i]) {
+				t.Errorf("value %v: got weights %v, want %v", test.value, b.Weights(), test.weights)
+				break
+			}
+		}
+	}
+	b.SetValue(1)
+	if !near(b.Duration(), 1.5) {
+		t.Errorf("got duration %v, want 1.5", b.Duration())
+	}
+}
+
+func TestBlendSpace2DWeights(t *testing.T) {
+	b := NewBlendSpace2D()
+	for _, p := range [][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+		if err := b.Add(NewAnimation("", nil, 1), p[0], p[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.Add(NewAnimation("", nil, 1), 1, 1) == nil {
+		t.Error("added a second animation at the same point")
+	}
+	tests := []struct {
+		x, y    float32
+		weights []float32
+	}{
+		{1, -1, []float32{0, 1, 0, 0}},
+		{-1, 1, []float32{0, 0, 0, 1}},
+		{3, 0, []float32{0, 0.5, 0.5, 0}},
+		{0, -3, []float32{0.5, 0.5, 0, 0}},
+	}
+	for _, test := range tests {
+		b.SetValue(test.x, test.y)
+		for i, w := range b.Weights() {
+			if !near(w, test.weights[i]) {
+				t.Errorf("value %v, %v: got weights %v, want %v", test.x, test.y, b.Weights(), test.weights)
+				break
+			}
+		}
+	}
+	b.SetValue(0.3, -0.2)
+	var sum float32
+	for _, w := range b.Weights() {
+		sum += w
+	}
+	if !near(sum, 1) {
+		t.Errorf("weights inside the hull sum to %v", sum)
+	}
+}
+
+func TestBlendSpaceMix(t *testing.T) {
+	data := loadTest(t)
+	_, walk := data.FindAnimation("walk")
+	_, idle := data.FindAnimation("idle")
+	skeleton := NewSkeleton(data)
+	b := NewBlendSpace1D()
+	b.Add(idle, 0)
+	b.Add(walk, 1)
+	b.SetValue(0.25)
+	b.SetPhase(0.5)
+	if err := b.Mix(skeleton, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Only walk keys the root, which it moves to 50 halfway through.
+	if x := skeleton.RootBone().X; !near(x, 12.5) {
+		t.Errorf("got root x %v, want 12.5", x)
+	}
+	// Only idle keys the torso, which it turns 2.5 degrees halfway through.
+	if _, torso := skeleton.FindBone("torso"); !near(torso.Rotation, 90+2.5*0.75) {
+		t.Errorf("got torso rotation %v, want %v", torso.Rotation, 90+2.5*0.75)
+	}
+}
+
+func TestBlendSpaceAveragesScale(t *testing.T) {
+	data := loadTest(t)
+	b := NewBlendSpace1D()
+	for i, scale := range []float32{2, 3} {
+		builder := NewAnimationBuilder("", data)
+		builder.Scale("hip", 0, scale, scale, CurveLinear)
+		builder.Scale("hip", 1, scale, scale, CurveLinear)
+		animation, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Add(animation, float32(i))
+	}
+	b.SetValue(0.5)
+	skeleton := NewSkeleton(data)
+	if err := b.Mix(skeleton, 1); err != nil {
+		t.Fatal(err)
+	}
+	if hip := skeleton.Bones[1]; !near(hip.ScaleX, 2.5) {
+		t.Errorf("got hip scale %v, want the average 2.5", hip.ScaleX)
+	}
+}
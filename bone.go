@@ -15,6 +15,8 @@ type BoneData struct {
 	rotation float32
 	scaleX   float32
 	scaleY   float32
+
+	skinRequired bool
 }
 
 func NewBoneData(name string, parent *BoneData) *BoneData {
@@ -26,6 +28,41 @@ func NewBoneData(name string, parent *BoneData) *BoneData {
 	return boneData
 }
 
+func (b *BoneData) Name() string {
+	return b.name
+}
+
+func (b *BoneData) Parent() *BoneData {
+	return b.parent
+}
+
+// X, Y, Rotation, ScaleX and ScaleY return the setup pose values.
+func (b *BoneData) X() float32 {
+	return b.x
+}
+
+func (b *BoneData) Y() float32 {
+	return b.y
+}
+
+func (b *BoneData) Rotation() float32 {
+	return b.rotation
+}
+
+func (b *BoneData) ScaleX() float32 {
+	return b.scaleX
+}
+
+func (b *BoneData) ScaleY() float32 {
+	return b.scaleY
+}
+
+// SkinRequired reports whether the bone is only active when the skeleton's
+// skin includes it.
+func (b *BoneData) SkinRequired() bool {
+	return b.skinRequired
+}
+
 type Bone struct {
 	name          string
 	Data          *BoneData
@@ -44,6 +81,7 @@ type Bone struct {
 	WorldRotation float32
 	WorldScaleX   float32
 	WorldScaleY   float32
+	active        bool
 }
 
 func NewBone(boneData *BoneData, parent *Bone) *Bone {
@@ -55,10 +93,25 @@ func NewBone(boneData *BoneData, parent *Bone) *Bone {
 	bone.ScaleY = 1
 	bone.WorldScaleX = 1
 	bone.WorldScaleY = 1
+	bone.active = true
 	bone.SetToSetupPose()
 	return bone
 }
 
+func (b *Bone) Name() string {
+	return b.name
+}
+
+func (b *Bone) Parent() *Bone {
+	return b.parent
+}
+
+// Active reports whether the bone is part of the skeleton with its current
+// skin. Inactive bones are not updated or animated.
+func (b *Bone) Active() bool {
+	return b.active
+}
+
 func (b *Bone) SetToSetupPose() {
 	data := b.Data
 	b.X = data.x
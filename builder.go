@@ -0,0 +1,341 @@
+package spine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type curveKind int
+
+const (
+	curveLinear curveKind = iota
+	curveStepped
+	curveBezier
+)
+
+// KeyCurve is how a key interpolates to the next key of its timeline.
+type KeyCurve struct {
+	kind               curveKind
+	cx1, cy1, cx2, cy2 float32
+	// channels holds a curve for each channel, if they differ.
+	channels []KeyCurve
+}
+
+var (
+	// CurveLinear changes the value evenly.
+	CurveLinear = KeyCurve{kind: curveLinear}
+	// CurveStepped holds the value until the next key.
+	CurveStepped = KeyCurve{kind: curveStepped}
+)
+
+// CurveBezier eases the value along a Bezier curve from (0, 0) to (1, 1)
+// with the given control points, as set by Curve.SetCurve.
+func CurveBezier(cx1, cy1, cx2, cy2 float32) KeyCurve {
+	return KeyCurve{kind: curveBezier, cx1: cx1, cy1: cy1, cx2: cx2, cy2: cy2}
+}
+
+// CurveChannels gives each channel of a key its own curve, in the order of
+// the values of the key, such as x then y for a translation.
+func CurveChannels(curves ...KeyCurve) KeyCurve {
+	return KeyCurve{channels: curves}
+}
+
+// easings are the cubic Bezier curves commonly used for these easing
+// functions, such as in CSS.
+var easings = map[string][4]float32{
+	"ease":           {0.25, 0.1, 0.25, 1},
+	"easeIn":         {0.42, 0, 1, 1},
+	"easeOut":        {0, 0, 0.58, 1},
+	"easeInOut":      {0.42, 0, 0.58, 1},
+	"easeInSine":     {0.12, 0, 0.39, 0},
+	"easeOutSine":    {0.61, 1, 0.88, 1},
+	"easeInOutSine":  {0.37, 0, 0.63, 1},
+	"easeInQuad":     {0.11, 0, 0.5, 0},
+	"easeOutQuad":    {0.5, 1, 0.89, 1},
+	"easeInOutQuad":  {0.45, 0, 0.55, 1},
+	"easeInCubic":    {0.32, 0, 0.67, 0},
+	"easeOutCubic":   {0.33, 1, 0.68, 1},
+	"easeInOutCubic": {0.65, 0, 0.35, 1},
+	"easeInQuart":    {0.5, 0, 0.75, 0},
+	"easeOutQuart":   {0.25, 1, 0.5, 1},
+	"easeInOutQuart": {0.76, 0, 0.24, 1},
+	"easeInQuint":    {0.64, 0, 0.78, 0},
+	"easeOutQuint":   {0.22, 1, 0.36, 1},
+	"easeInOutQuint": {0.83, 0, 0.17, 1},
+	"easeInExpo":     {0.7, 0, 0.84, 0},
+	"easeOutExpo":    {0.16, 1, 0.3, 1},
+	"easeInOutExpo":  {0.87, 0, 0.13, 1},
+	"easeInCirc":     {0.55, 0, 1, 0.45},
+	"easeOutCirc":    {0, 0.55, 0.45, 1},
+	"easeInOutCirc":  {0.85, 0, 0.15, 1},
+	"easeInBack":     {0.36, 0, 0.66, -0.56},
+	"easeOutBack":    {0.34, 1.56, 0.64, 1},
+	"easeInOutBack":  {0.68, -0.6, 0.32, 1.6},
+}
+
+// CurveEasing returns the Bezier curve for a named easing function: "ease",
+// "easeIn", "easeOut" or "easeInOut" as in CSS, or one of those followed by
+// Sine, Quad, Cubic, Quart, Quint, Expo, Circ or Back, such as
+// "easeInOutCubic".
+func CurveEasing(name string) (KeyCurve, error) {
+	p, ok := easings[name]
+	if !ok {
+		return KeyCurve{}, errors.New("spine: unknown easing: " + name)
+	}
+	return CurveBezier(p[0], p[1], p[2], p[3]), nil
+}
+
+func (k KeyCurve) set(curve *Curve, index int) {
+	if k.channels != nil {
+		for channel, c := range k.channels {
+			c.setChannel(curve, channel, index)
+		}
+		return
+	}
+	switch k.kind {
+	case curveStepped:
+		curve.SetStepped(index)
+	case curveBezier:
+		curve.SetCurve(index, k.cx1, k.cy1, k.cx2, k.cy2)
+	default:
+		curve.SetLinear(index)
+	}
+}
+
+func (k KeyCurve) setChannel(curve *Curve, channel, index int) {
+	switch k.kind {
+	case curveStepped:
+		curve.SetChannelStepped(channel, index)
+	case curveBezier:
+		curve.SetChannelCurve(channel, index, k.cx1, k.cy1, k.cx2, k.cy2)
+	default:
+		curve.SetChannelLinear(channel, index)
+	}
+}
+
+type builderTimelineType int
+
+const (
+	builderRotate builderTimelineType = iota
+	builderTranslate
+	builderScale
+	builderColor
+	builderAttachment
+)
+
+// builderChannels is the number of values in a key of each type.
+var builderChannels = [...]int{
+	builderRotate:     1,
+	builderTranslate:  2,
+	builderScale:      2,
+	builderColor:      4,
+	builderAttachment: 1,
+}
+
+type builderKey struct {
+	time       float32
+	values     [4]float32
+	attachment string
+	curve      KeyCurve
+}
+
+type builderTimeline struct {
+	timelineType builderTimelineType
+	index        int
+	keys         []builderKey
+}
+
+// AnimationBuilder builds an animation for a skeleton in code, naming the
+// bones, slots and events it animates. Keys can be added in any order. The
+// curve of a key is used from it to the next key of the same timeline.
+//
+// The first error, such as a name that is not found, is kept and returned by
+// Build, so keys can be added without checking each one.
+type AnimationBuilder struct {
+	data      *SkeletonData
+	name      string
+	timelines []*builderTimeline
+	events    []*Event
+	err       error
+}
+
+func NewAnimationBuilder(name string, data *SkeletonData) *AnimationBuilder {
+	return &AnimationBuilder{data: data, name: name}
+}
+
+func (b *AnimationBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// timeline returns the timeline of the given type for a bone or slot,
+// adding it if needed, or nil after an error.
+func (b *AnimationBuilder) timeline(timelineType builderTimelineType, name string, time float32, curve KeyCurve) *builderTimeline {
+	if time < 0 {
+		b.fail(fmt.Errorf("spine: negative key time %v", time))
+		return nil
+	}
+	if channels := builderChannels[timelineType]; curve.channels != nil && len(curve.channels) != channels {
+		b.fail(fmt.Errorf("spine: key has %d channel curves, want %d", len(curve.channels), channels))
+		return nil
+	}
+	var index int
+	if timelineType == builderColor || timelineType == builderAttachment {
+		if index, _ = b.data.FindSlot(name); index == -1 {
+			b.fail(fmt.Errorf("%w: %s", ErrSlotNotFound, name))
+			return nil
+		}
+	} else if index, _ = b.data.FindBone(name); index == -1 {
+		b.fail(fmt.Errorf("%w: %s", ErrBoneNotFound, name))
+		return nil
+	}
+	for _, t := range b.timelines {
+		if t.timelineType == timelineType && t.index == index {
+			return t
+		}
+	}
+	t := &builderTimeline{timelineType: timelineType, index: index}
+	b.timelines = append(b.timelines, t)
+	return t
+}
+
+// Rotate adds a key for a bone's rotation, in degrees relative to the setup
+// pose.
+func (b *AnimationBuilder) Rotate(bone string, time, angle float32, curve KeyCurve) {
+	if t := b.timeline(builderRotate, bone, time, curve); t != nil {
+		t.keys = append(t.keys, builderKey{time: time, values: [4]float32{angle}, curve: curve})
+	}
+}
+
+// Translate adds a key for a bone's position, relative to the setup pose.
+func (b *AnimationBuilder) Translate(bone string, time, x, y float32, curve KeyCurve) {
+	if t := b.timeline(builderTranslate, bone, time, curve); t != nil {
+		t.keys = append(t.keys, builderKey{time: time, values: [4]float32{x, y}, curve: curve})
+	}
+}
+
+// Scale adds a key for a bone's scale, as a multiple of the setup pose.
+func (b *AnimationBuilder) Scale(bone string, time, x, y float32, curve KeyCurve) {
+	if t := b.timeline(builderScale, bone, time, curve); t != nil {
+		t.keys = append(t.keys, builderKey{time: time, values: [4]float32{x, y}, curve: curve})
+	}
+}
+
+// Color adds a key for a slot's color.
+func (b *AnimationBuilder) Color(slot string, time, red, green, blue, alpha float32, curve KeyCurve) {
+	if t := b.timeline(builderColor, slot, time, curve); t != nil {
+		t.keys = append(t.keys, builderKey{time: time, values: [4]float32{red, green, blue, alpha}, curve: curve})
+	}
+}
+
+// Attachment adds a key that shows the named attachment in a slot, or
+// hides the slot's attachment if name is "".
+func (b *AnimationBuilder) Attachment(slot string, time float32, name string) {
+	if t := b.timeline(builderAttachment, slot, time, CurveStepped); t != nil {
+		t.keys = append(t.keys, builderKey{time: time, attachment: name})
+	}
+}
+
+// Event adds the named event at time, with the values of its event data.
+// The event returned can be changed until Build is called.
+func (b *AnimationBuilder) Event(time float32, name string) *Event {
+	if time < 0 {
+		b.fail(fmt.Errorf("spine: negative key time %v", time))
+		return &Event{Time: time}
+	}
+	_, data := b.data.FindEvent(name)
+	if data == nil {
+		b.fail(errors.New("spine: event not found: " + name))
+		return &Event{Time: time}
+	}
+	event := NewEvent(time, data)
+	b.events = append(b.events, event)
+	return event
+}
+
+// Build returns the animation, which lasts until its last key, or the first
+// error from adding keys. Two keys of a timeline at the same time are an
+// error.
+func (b *AnimationBuilder) Build() (*Animation, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	var duration float32
+	timelines := make([]Timeline, 0, len(b.timelines)+1)
+	for _, t := range b.timelines {
+		keys := t.keys
+		sort.SliceStable(keys, func(i, j int) bool { return keys[i].time < keys[j].time })
+		for i := 1; i < len(keys); i++ {
+			if keys[i].time == keys[i-1].time {
+				return nil, fmt.Errorf("spine: two keys at time %v", keys[i].time)
+			}
+		}
+		if last := keys[len(keys)-1].time; last > duration {
+			duration = last
+		}
+		timelines = append(timelines, t.build(b.data))
+	}
+	if len(b.events) > 0 {
+		events := b.events
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Time < events[j].Time })
+		timeline := NewEventTimeline(len(events))
+		for i, event := range events {
+			timeline.setFrame(i, event)
+		}
+		if last := events[len(events)-1].Time; last > duration {
+			duration = last
+		}
+		timelines = append(timelines, timeline)
+	}
+	return NewAnimation(b.name, timelines, duration), nil
+}
+
+func (t *builderTimeline) build(data *SkeletonData) Timeline {
+	n := len(t.keys)
+	var curve *Curve
+	var timeline Timeline
+	switch t.timelineType {
+	case builderRotate:
+		rotate := NewRotateTimeline(n)
+		rotate.boneIndex = t.index
+		for i, k := range t.keys {
+			rotate.setFrame(i, k.time, k.values[0])
+		}
+		timeline, curve = rotate, rotate.curve
+	case builderTranslate:
+		translate := NewTranslateTimeline(n)
+		translate.boneIndex = t.index
+		for i, k := range t.keys {
+			translate.setFrame(i, k.time, k.values[0], k.values[1])
+		}
+		timeline, curve = translate, translate.curve
+	case builderScale:
+		scale := NewScaleTimeline(n)
+		scale.boneIndex = t.index
+		for i, k := range t.keys {
+			scale.setFrame(i, k.time, k.values[0], k.values[1])
+		}
+		timeline, curve = scale, scale.curve
+	case builderColor:
+		color := NewColorTimeline(n)
+		color.slotIndex = t.index
+		for i, k := range t.keys {
+			color.setFrame(i, k.time, k.values[0], k.values[1], k.values[2], k.values[3])
+		}
+		timeline, curve = color, color.curve
+	case builderAttachment:
+		attachment := NewAttachmentTimeline(n)
+		attachment.slotIndex = t.index
+		for i, k := range t.keys {
+			attachment.setFrame(i, k.time, k.attachment)
+		}
+		attachment.resolve(data)
+		return attachment
+	}
+	for i, k := range t.keys[:n-1] {
+		k.curve.set(curve, i)
+	}
+	return timeline
+}
@@ -0,0 +1,75 @@
+package spine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnimationBuilder(t *testing.T) {
+	data := loadTest(t)
+	b := NewAnimationBuilder("wave", data)
+	b.Rotate("hip", 2, 40, CurveLinear)
+	b.Rotate("hip", 0, 0, CurveLinear)
+	b.Translate("root", 0, 0, 0, CurveChannels(CurveStepped, CurveLinear))
+	b.Translate("root", 1, 10, 20, CurveLinear)
+	b.Attachment("hat", 0.5, "hat")
+	b.Event(1.5, "shout").String = "wave"
+	animation, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if animation.Name() != "wave" || animation.Duration() != 2 {
+		t.Errorf("got %s of %v, want wave of 2", animation.Name(), animation.Duration())
+	}
+
+	skeleton := NewSkeleton(data)
+	skeleton.SetSkinByName("fancy")
+	animation.Apply(skeleton, 0.5, false)
+	if _, hip := skeleton.FindBone("hip"); !near(hip.Rotation, 10) {
+		t.Errorf("got hip rotation %v, want 10", hip.Rotation)
+	}
+	if root := skeleton.RootBone(); !near(root.X, 0) || !near(root.Y, 10) {
+		t.Errorf("got root at %v, %v, want x held at 0 and y at 10", root.X, root.Y)
+	}
+	if _, hat := skeleton.FindSlot("hat"); hat.Attachment == nil || hat.Attachment.Name() != "hat" {
+		t.Errorf("got hat attachment %v", hat.Attachment)
+	}
+
+	var events []*Event
+	for _, timeline := range animation.timelines {
+		if t, ok := timeline.(*EventTimeline); ok {
+			events = t.events
+		}
+	}
+	if len(events) != 1 || events[0].Time != 1.5 || events[0].String != "wave" {
+		t.Errorf("got events %+v", events)
+	}
+}
+
+func TestAnimationBuilderErrors(t *testing.T) {
+	data := loadTest(t)
+
+	b := NewAnimationBuilder("", data)
+	b.Rotate("tail", 0, 0, CurveLinear)
+	b.Event(0, "missing")
+	if _, err := b.Build(); !errors.Is(err, ErrBoneNotFound) {
+		t.Errorf("got %v, want the first error, a missing bone", err)
+	}
+
+	b = NewAnimationBuilder("", data)
+	b.Scale("hip", 1, 1, 1, CurveLinear)
+	b.Scale("hip", 1, 2, 2, CurveLinear)
+	if _, err := b.Build(); err == nil {
+		t.Error("built two keys at the same time")
+	}
+
+	b = NewAnimationBuilder("", data)
+	b.Translate("hip", 0, 0, 0, CurveChannels(CurveLinear))
+	if _, err := b.Build(); err == nil {
+		t.Error("built a translation with one channel curve")
+	}
+
+	if _, err := CurveEasing("bounce"); err == nil {
+		t.Error("found an unknown easing")
+	}
+}
@@ -0,0 +1,327 @@
+package spine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The compact encoding stores times as 16 bit fractions of the duration and
+// each channel of a timeline as 16 bit steps between its smallest and
+// largest value. Bezier control points are stored in 1/4096 steps.
+const (
+	compactEncodingVersion = 2
+	compactSteps           = 65535
+	compactCurveScale      = 4096
+)
+
+const (
+	compactRotate byte = iota + 1
+	compactTranslate
+	compactScale
+	compactColor
+	compactAttachment
+	compactEvent
+)
+
+const (
+	compactLinear byte = iota
+	compactStepped
+	compactBezier
+)
+
+var errCompactTruncated = errors.New("spine: truncated animation data")
+
+// EncodeAnimation encodes the animation compactly for shipping, quantizing
+// times and values to 16 bits. It is usually given the result of Reduce.
+// Timelines of types this package does not define cannot be encoded.
+func EncodeAnimation(animation *Animation) ([]byte, error) {
+	data := []byte{compactEncodingVersion}
+	data = appendString(data, animation.name)
+	data = appendFloats(data, animation.duration)
+	data = binary.AppendUvarint(data, uint64(len(animation.timelines)))
+	e := compactEncoder{duration: animation.duration}
+	for _, timeline := range animation.timelines {
+		var err error
+		switch t := timeline.(type) {
+		case *RotateTimeline:
+			data, err = e.appendCurved(data, compactRotate, t.boneIndex, t.frames, 2, t.curve)
+		case *TranslateTimeline:
+			data, err = e.appendCurved(data, compactTranslate, t.boneIndex, t.frames, 3, t.curve)
+		case *ScaleTimeline:
+			data, err = e.appendCurved(data, compactScale, t.boneIndex, t.frames, 3, t.curve)
+		case *ColorTimeline:
+			data, err = e.appendCurved(data, compactColor, t.slotIndex, t.frames, 5, t.curve)
+		case *AttachmentTimeline:
+			data = append(data, compactAttachment)
+			data = binary.AppendUvarint(data, uint64(t.slotIndex))
+			data = binary.AppendUvarint(data, uint64(len(t.frames)))
+			for i, time := range t.frames {
+				data = e.appendTime(data, time)
+				data = appendString(data, t.attachmentNames[i])
+			}
+		case *EventTimeline:
+			data = append(data, compactEvent)
+			data = binary.AppendUvarint(data, uint64(len(t.frames)))
+			for _, event := range t.events {
+				data = e.appendTime(data, event.Time)
+				data = appendString(data, event.data.name)
+				data = binary.AppendVarint(data, int64(event.Int))
+				data = appendFloats(data, event.Float)
+				data = appendString(data, event.String)
+				data = appendFloats(data, event.Volume, event.Balance)
+			}
+		default:
+			return nil, fmt.Errorf("spine: cannot encode timeline of type %T", timeline)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+type compactEncoder struct {
+	duration float32
+}
+
+func (e compactEncoder) quantizeTime(time float32) uint16 {
+	if e.duration > 0 {
+		return quantize(time/e.duration, 0, 1)
+	}
+	return 0
+}
+
+func (e compactEncoder) appendTime(data []byte, time float32) []byte {
+	return binary.LittleEndian.AppendUint16(data, e.quantizeTime(time))
+}
+
+// appendCurved encodes a timeline whose frames are a time followed by
+// stride-1 channel values, along with its curve. Keys too close together to
+// keep apart once quantized are an error, as the span between them would
+// decode with no length.
+func (e compactEncoder) appendCurved(data []byte, kind byte, index int, frames []float32, stride int, curve *Curve) ([]byte, error) {
+	for i := stride; i < len(frames); i += stride {
+		if e.quantizeTime(frames[i]) == e.quantizeTime(frames[i-stride]) {
+			return nil, fmt.Errorf("spine: keys at %v and %v are too close together to encode", frames[i-stride], frames[i])
+		}
+	}
+
+	data = append(data, kind)
+	data = binary.AppendUvarint(data, uint64(index))
+	frameCount := len(frames) / stride
+	data = binary.AppendUvarint(data, uint64(frameCount))
+
+	mins := make([]float32, stride)
+	maxs := make([]float32, stride)
+	for c := 1; c < stride; c++ {
+		mins[c], maxs[c] = frames[c], frames[c]
+		for i := c; i < len(frames); i += stride {
+			mins[c] = float32(math.Min(float64(mins[c]), float64(frames[i])))
+			maxs[c] = float32(math.Max(float64(maxs[c]), float64(frames[i])))
+		}
+		data = appendFloats(data, mins[c], maxs[c])
+	}
+	for i := 0; i < len(frames); i += stride {
+		data = e.appendTime(data, frames[i])
+		for c := 1; c < stride; c++ {
+			data = binary.LittleEndian.AppendUint16(data, quantize(frames[i+c], mins[c], maxs[c]))
+		}
+	}
+
+	data = appendSegments(data, curve.curves)
+	// Channels with their own curve follow.
+	data = append(data, byte(len(curve.channels)))
+	for _, curves := range curve.channels {
+		data = appendSegments(data, curves)
+	}
+	return data, nil
+}
+
+func appendSegments(data []byte, curves []float32) []byte {
+	for i := 0; i < len(curves)/curveSegment; i++ {
+		switch curves[i*curveSegment] {
+		case 0:
+			data = append(data, compactLinear)
+		case -1:
+			data = append(data, compactStepped)
+		default:
+			data = append(data, compactBezier)
+			cx1, cy1, cx2, cy2 := bezierPoints(curves, i)
+			for _, v := range [...]float32{cx1, cy1, cx2, cy2} {
+				v = float32(math.Round(float64(v * compactCurveScale)))
+				v = float32(math.Max(math.MinInt16, math.Min(math.MaxInt16, float64(v))))
+				data = binary.LittleEndian.AppendUint16(data, uint16(int16(v)))
+			}
+		}
+	}
+	return data
+}
+
+func quantize(value, min, max float32) uint16 {
+	if max <= min {
+		return 0
+	}
+	return uint16(math.Round(float64((value - min) / (max - min) * compactSteps)))
+}
+
+func dequantize(q uint16, min, max float32) float32 {
+	return min + (max-min)*float32(q)/compactSteps
+}
+
+func appendString(data []byte, s string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(s)))
+	return append(data, s...)
+}
+
+// DecodeAnimation decodes an animation encoded by EncodeAnimation for the
+// skeleton data it was encoded from, which resolves attachments and events.
+func DecodeAnimation(data []byte, skeletonData *SkeletonData) (*Animation, error) {
+	d := &binaryDecoder{data: data, truncated: errCompactTruncated}
+	if version := d.byte(); version != compactEncodingVersion && d.err == nil {
+		return nil, errors.New("spine: unknown animation encoding version")
+	}
+	name := d.string()
+	duration := d.float()
+	timelines := make([]Timeline, 0, d.count(4))
+	for i := cap(timelines); i > 0 && d.err == nil; i-- {
+		timeline, err := decodeTimeline(d, skeletonData, duration)
+		if err != nil {
+			return nil, err
+		}
+		timelines = append(timelines, timeline)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return NewAnimation(name, timelines, duration), nil
+}
+
+func decodeTimeline(d *binaryDecoder, skeletonData *SkeletonData, duration float32) (Timeline, error) {
+	time := func() float32 {
+		return duration * float32(d.uint16()) / compactSteps
+	}
+	kind := d.byte()
+	if d.err == nil && (kind < compactRotate || kind > compactEvent) {
+		return nil, fmt.Errorf("spine: unknown encoded timeline type %d", kind)
+	}
+	index := 0
+	switch kind {
+	case compactRotate, compactTranslate, compactScale:
+		if index = d.count(0); d.err == nil && index >= len(skeletonData.bones) {
+			return nil, errors.New("spine: encoded animation does not match skeleton")
+		}
+	case compactColor, compactAttachment:
+		if index = d.count(0); d.err == nil && index >= len(skeletonData.slots) {
+			return nil, errors.New("spine: encoded animation does not match skeleton")
+		}
+	}
+	// Each frame takes at least its time and one byte per value.
+	frameCount := d.count(3)
+	if d.err != nil {
+		return nil, d.err
+	}
+	if frameCount == 0 {
+		return nil, errors.New("spine: encoded timeline has no frames")
+	}
+
+	switch kind {
+	case compactRotate:
+		t := NewRotateTimeline(frameCount)
+		t.boneIndex = index
+		decodeCurved(d, t.frames, 2, t.curve, time)
+		return t, d.err
+	case compactTranslate:
+		t := NewTranslateTimeline(frameCount)
+		t.boneIndex = index
+		decodeCurved(d, t.frames, 3, t.curve, time)
+		return t, d.err
+	case compactScale:
+		t := NewScaleTimeline(frameCount)
+		t.boneIndex = index
+		decodeCurved(d, t.frames, 3, t.curve, time)
+		return t, d.err
+	case compactColor:
+		t := NewColorTimeline(frameCount)
+		t.slotIndex = index
+		decodeCurved(d, t.frames, 5, t.curve, time)
+		return t, d.err
+	case compactAttachment:
+		t := NewAttachmentTimeline(frameCount)
+		t.slotIndex = index
+		for i := range t.frames {
+			t.setFrame(i, time(), d.string())
+		}
+		t.resolve(skeletonData)
+		return t, d.err
+	case compactEvent:
+		t := NewEventTimeline(frameCount)
+		for i := range t.frames {
+			eventTime := time()
+			name := d.string()
+			if d.err != nil {
+				return nil, d.err
+			}
+			_, eventData := skeletonData.FindEvent(name)
+			if eventData == nil {
+				return nil, errors.New("spine: encoded event not found: " + name)
+			}
+			event := NewEvent(eventTime, eventData)
+			event.Int = int(d.varint())
+			event.Float = d.float()
+			event.String = d.string()
+			event.Volume = d.float()
+			event.Balance = d.float()
+			t.setFrame(i, event)
+		}
+		return t, d.err
+	}
+	return nil, d.err
+}
+
+// decodeCurved reads what appendCurved wrote after the frame count into
+// frames and curve, which are already sized for it.
+func decodeCurved(d *binaryDecoder, frames []float32, stride int, curve *Curve, time func() float32) {
+	mins := make([]float32, stride)
+	maxs := make([]float32, stride)
+	for c := 1; c < stride; c++ {
+		mins[c], maxs[c] = d.float(), d.float()
+	}
+	for i := 0; i < len(frames); i += stride {
+		frames[i] = time()
+		for c := 1; c < stride; c++ {
+			frames[i+c] = dequantize(d.uint16(), mins[c], maxs[c])
+		}
+	}
+	decodeSegments(d, curve, 0)
+	channels := int(d.byte())
+	if d.err == nil && channels > stride-2 {
+		d.err = errors.New("spine: encoded curve has too many channels")
+		return
+	}
+	for channel := 1; channel <= channels && d.err == nil; channel++ {
+		decodeSegments(d, curve, channel)
+	}
+}
+
+func decodeSegments(d *binaryDecoder, curve *Curve, channel int) {
+	for i := 0; i < curve.FrameCount()-1; i++ {
+		switch d.byte() {
+		case compactLinear:
+			curve.SetChannelLinear(channel, i)
+		case compactStepped:
+			curve.SetChannelStepped(channel, i)
+		case compactBezier:
+			var v [4]float32
+			for j := range v {
+				v[j] = float32(int16(d.uint16())) / compactCurveScale
+			}
+			curve.SetChannelCurve(channel, i, v[0], v[1], v[2], v[3])
+		default:
+			if d.err == nil {
+				d.err = errors.New("spine: unknown encoded curve type")
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package spine
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	data := loadTest(t)
+	_, walk := data.FindAnimation("walk")
+	encoded, err := EncodeAnimation(walk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeAnimation(encoded, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name() != "walk" || decoded.Duration() != walk.Duration() || len(decoded.timelines) != len(walk.timelines) {
+		t.Fatalf("got %s of %v with %d timelines", decoded.Name(), decoded.Duration(), len(decoded.timelines))
+	}
+
+	want, got := NewSkeleton(data), NewSkeleton(data)
+	// Times are quantized, so keys are not sampled exactly.
+	for _, time := range []float32{0, 0.2, 0.45, 0.55, 0.7, 1} {
+		walk.Apply(want, time, false)
+		decoded.Apply(got, time, false)
+		for i, bone := range want.Bones {
+			other := got.Bones[i]
+			if !nearQuantized(bone.X, other.X) || !nearQuantized(bone.Rotation, other.Rotation) || !nearQuantized(bone.ScaleX, other.ScaleX) {
+				t.Errorf("at %v: bone %s is %+v, want %+v", time, bone.name, other, bone)
+			}
+		}
+		for i, slot := range want.Slots {
+			other := got.Slots[i]
+			if !nearQuantized(slot.A, other.A) || slot.Attachment != other.Attachment {
+				t.Errorf("at %v: slot %s differs", time, slot.data.name)
+			}
+		}
+	}
+
+	var events []*Event
+	for _, timeline := range decoded.timelines {
+		if t, ok := timeline.(*EventTimeline); ok {
+			events = t.events
+		}
+	}
+	if len(events) != 3 || events[1].String != "ho" || events[2].Int != 2 {
+		t.Errorf("got events %+v", events)
+	}
+
+	for _, timeline := range decoded.timelines {
+		if rotate, ok := timeline.(*RotateTimeline); ok && rotate.boneIndex == 1 {
+			cx1, cy1, cx2, cy2 := bezierPoints(rotate.curve.curves, 0)
+			if got := [4]float32{cx1, cy1, cx2, cy2}; got != [4]float32{0.25, 0, 0.75, 1} {
+				t.Errorf("got hip curve %v, want 0.25, 0, 0.75, 1", got)
+			}
+		}
+	}
+
+	reencoded, err := EncodeAnimation(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Error("encoding a decoded animation changed it")
+	}
+}
+
+// nearQuantized reports whether a decoded value is within quantization error.
+func nearQuantized(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 0.01
+}
+
+func TestCompactTruncated(t *testing.T) {
+	data := loadTest(t)
+	_, walk := data.FindAnimation("walk")
+	encoded, err := EncodeAnimation(walk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < len(encoded); n++ {
+		if _, err := DecodeAnimation(encoded[:n], data); err == nil {
+			t.Fatalf("decoded %d of %d bytes", n, len(encoded))
+		}
+	}
+	if _, err := DecodeAnimation(encoded[:len(encoded)-1], data); !errors.Is(err, errCompactTruncated) {
+		t.Errorf("got %v, want a truncation error", err)
+	}
+
+	encoded[0] = 1
+	if _, err := DecodeAnimation(encoded, data); err == nil {
+		t.Error("decoded an unknown version")
+	}
+}
+
+func TestCompactRejectsCollidingKeys(t *testing.T) {
+	rotate := NewRotateTimeline(3)
+	rotate.setFrame(0, 0, 0)
+	rotate.setFrame(1, 0.5, 90)
+	rotate.setFrame(2, 0.5000001, 180)
+	if _, err := EncodeAnimation(NewAnimation("", []Timeline{rotate}, 100)); err == nil {
+		t.Error("encoded keys that quantize to the same time")
+	}
+}
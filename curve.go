@@ -1,28 +1,137 @@
 package spine
 
+import "math"
+
+// curveSegment is the number of values stored for the curve between two
+// frames: 0 for linear or -1 for stepped, or for a Bezier curve the forward
+// differences that approximate it with 10 line segments followed by its
+// control points cx1, cy1, cx2 and cy2.
+const curveSegment = 10
+
+// Curve holds how a timeline interpolates between each pair of frames. All
+// channels of a timeline, such as x and y of a translation, share a curve
+// unless a channel is given its own with one of the SetChannel methods.
 type Curve struct {
 	curves []float32
+	// channels holds the curves of channels 1 and up once they have their
+	// own. Channel 0 always uses curves.
+	channels [][]float32
+	exact    bool
 }
 
 func NewCurve(frameCount int) *Curve {
 	curve := new(Curve)
-	curve.curves = make([]float32, (frameCount-1)*6)
+	curve.curves = make([]float32, (frameCount-1)*curveSegment)
 	return curve
 }
 
-func (c *Curve) frameCount() int {
-	return len(c.curves)/6 + 1
+func (c *Curve) FrameCount() int {
+	return len(c.curves)/curveSegment + 1
+}
+
+// Channels returns how many channels have their own curve, counting
+// channel 0. Channels beyond these use the curve of channel 0.
+func (c *Curve) Channels() int {
+	return len(c.channels) + 1
+}
+
+// Exact reports whether Bezier curves are evaluated exactly.
+func (c *Curve) Exact() bool {
+	return c.exact
+}
+
+// SetExact selects exact evaluation of Bezier curves, rather than the
+// default approximation by 10 line segments, which can wobble visibly on
+// long, slow eases. Exact evaluation is a few times slower.
+func (c *Curve) SetExact(exact bool) {
+	c.exact = exact
 }
 
+// SetLinear, SetStepped and SetCurve set the curve of every channel.
+
 func (c *Curve) SetLinear(index int) {
-	c.curves[index*6] = 0
+	for channel := 0; channel < c.Channels(); channel++ {
+		c.channel(channel)[index*curveSegment] = 0
+	}
 }
 
 func (c *Curve) SetStepped(index int) {
-	c.curves[index*6] = -1
+	for channel := 0; channel < c.Channels(); channel++ {
+		c.channel(channel)[index*curveSegment] = -1
+	}
 }
 
 func (c *Curve) SetCurve(index int, cx1, cy1, cx2, cy2 float32) {
+	for channel := 0; channel < c.Channels(); channel++ {
+		setBezier(c.channel(channel), index, cx1, cy1, cx2, cy2)
+	}
+}
+
+// SetChannelLinear, SetChannelStepped and SetChannelCurve set the curve of
+// one channel, which has its own curve from then on.
+
+func (c *Curve) SetChannelLinear(channel, index int) {
+	c.split(channel + 1)
+	c.channel(channel)[index*curveSegment] = 0
+}
+
+func (c *Curve) SetChannelStepped(channel, index int) {
+	c.split(channel + 1)
+	c.channel(channel)[index*curveSegment] = -1
+}
+
+func (c *Curve) SetChannelCurve(channel, index int, cx1, cy1, cx2, cy2 float32) {
+	c.split(channel + 1)
+	setBezier(c.channel(channel), index, cx1, cy1, cx2, cy2)
+}
+
+// split gives at least the first n channels their own curve, starting as a
+// copy of channel 0.
+func (c *Curve) split(n int) {
+	for len(c.channels) < n-1 {
+		c.channels = append(c.channels, append([]float32(nil), c.curves...))
+	}
+}
+
+func (c *Curve) channel(channel int) []float32 {
+	if channel == 0 || channel > len(c.channels) {
+		return c.curves
+	}
+	return c.channels[channel-1]
+}
+
+func (c *Curve) clone() *Curve {
+	clone := &Curve{curves: append([]float32(nil), c.curves...), exact: c.exact}
+	for _, curves := range c.channels {
+		clone.channels = append(clone.channels, append([]float32(nil), curves...))
+	}
+	return clone
+}
+
+// isStepped reports whether every channel is stepped at index.
+func (c *Curve) isStepped(index int) bool {
+	for channel := 0; channel < c.Channels(); channel++ {
+		if c.channel(channel)[index*curveSegment] != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// anyStepped reports whether any channel is stepped at index.
+func (c *Curve) anyStepped(index int) bool {
+	for channel := 0; channel < c.Channels(); channel++ {
+		if c.channel(channel)[index*curveSegment] == -1 {
+			return true
+		}
+	}
+	return false
+}
+
+// setBezier stores a Bezier segment as the forward differences that
+// approximate it with 10 line segments, and as its control points for exact
+// evaluation and encoding.
+func setBezier(curves []float32, index int, cx1, cy1, cx2, cy2 float32) {
 	subdiv_step := float32(1) / 10
 	subdiv_step2 := subdiv_step * subdiv_step
 	subdiv_step3 := subdiv_step2 * subdiv_step
@@ -34,25 +143,57 @@ func (c *Curve) SetCurve(index int, cx1, cy1, cx2, cy2 float32) {
 	tmp1y := -cy1*2 + cy2
 	tmp2x := (cx1-cx2)*3 + 1
 	tmp2y := (cy1-cy2)*3 + 1
-	i := index * 6
-	curves := c.curves
+	i := index * curveSegment
 	curves[i] = cx1*pre1 + tmp1x*pre2 + tmp2x*subdiv_step3
 	curves[i+1] = cy1*pre1 + tmp1y*pre2 + tmp2y*subdiv_step3
 	curves[i+2] = tmp1x*pre4 + tmp2x*pre5
 	curves[i+3] = tmp1y*pre4 + tmp2y*pre5
 	curves[i+4] = tmp2x * pre5
 	curves[i+5] = tmp2y * pre5
+	curves[i+6] = cx1
+	curves[i+7] = cy1
+	curves[i+8] = cx2
+	curves[i+9] = cy2
+}
+
+// bezierPoints returns the control points of a Bezier segment.
+func bezierPoints(curves []float32, index int) (cx1, cy1, cx2, cy2 float32) {
+	i := index * curveSegment
+	return curves[i+6], curves[i+7], curves[i+8], curves[i+9]
 }
 
+// CurvePercent maps percent, how far time is between frame index and the
+// next, to how far the value of channel 0 has changed.
 func (c *Curve) CurvePercent(index int, percent float32) float32 {
+	return c.percent(c.curves, index, percent)
+}
+
+// ChannelPercent is like CurvePercent for the given channel.
+func (c *Curve) ChannelPercent(channel, index int, percent float32) float32 {
+	return c.percent(c.channel(channel), index, percent)
+}
+
+// percents sets the percent of each channel, computing it only once when
+// the channels share a curve.
+func (c *Curve) percents(index int, percent float32, percents []float32) {
+	percents[0] = c.percent(c.curves, index, percent)
+	for channel := 1; channel < len(percents); channel++ {
+		if channel > len(c.channels) {
+			percents[channel] = percents[0]
+		} else {
+			percents[channel] = c.percent(c.channels[channel-1], index, percent)
+		}
+	}
+}
+
+func (c *Curve) percent(curves []float32, index int, percent float32) float32 {
 	if percent < 0 {
 		percent = 0
 	} else if percent > 1 {
 		percent = 1
 	}
 
-	curveIndex := index * 6
-	curves := c.curves
+	curveIndex := index * curveSegment
 	dfx := curves[curveIndex]
 	if dfx == 0 {
 		return percent
@@ -60,6 +201,9 @@ func (c *Curve) CurvePercent(index int, percent float32) float32 {
 	if dfx == -1 {
 		return 0
 	}
+	if c.exact {
+		return exactPercent(curves, index, percent)
+	}
 
 	dfy := curves[curveIndex+1]
 	ddfx := curves[curveIndex+2]
@@ -89,3 +233,37 @@ func (c *Curve) CurvePercent(index int, percent float32) float32 {
 	}
 	return y + (1-y)*(percent-x)/(1-x)
 }
+
+// exactPercent solves the Bezier segment for the t at which x is percent,
+// by Newton's method where it converges and bisection where it does not,
+// and returns y at that t.
+func exactPercent(curves []float32, index int, percent float32) float32 {
+	cx1, cy1, cx2, cy2 := bezierPoints(curves, index)
+	bezier := func(t, c1, c2 float64) float64 {
+		u := 1 - t
+		return 3*u*u*t*c1 + 3*u*t*t*c2 + t*t*t
+	}
+	x1, x2 := float64(cx1), float64(cx2)
+	target := float64(percent)
+	low, high := 0.0, 1.0
+	t := target
+	for i := 0; i < 32; i++ {
+		x := bezier(t, x1, x2) - target
+		if math.Abs(x) < 1e-7 {
+			break
+		}
+		if x < 0 {
+			low = t
+		} else {
+			high = t
+		}
+		u := 1 - t
+		slope := 3*u*u*x1 + 6*u*t*(x2-x1) + 3*t*t*(1-x2)
+		next := t - x/slope
+		if slope == 0 || next <= low || next >= high {
+			next = (low + high) / 2
+		}
+		t = next
+	}
+	return float32(bezier(t, float64(cy1), float64(cy2)))
+}
@@ -0,0 +1,128 @@
+package spine
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// bezierY solves the Bezier from (0, 0) to (1, 1) for x by bisection and
+// returns y there.
+func bezierY(cx1, cy1, cx2, cy2, x float64) float64 {
+	bezier := func(t, c1, c2 float64) float64 {
+		u := 1 - t
+		return 3*u*u*t*c1 + 3*u*t*t*c2 + t*t*t
+	}
+	low, high := 0.0, 1.0
+	for i := 0; i < 60; i++ {
+		t := (low + high) / 2
+		if bezier(t, cx1, cx2) < x {
+			low = t
+		} else {
+			high = t
+		}
+	}
+	return bezier((low+high)/2, cy1, cy2)
+}
+
+func TestCurveExact(t *testing.T) {
+	curve := NewCurve(2)
+	curve.SetCurve(0, 0.7, 0, 0.84, 0)
+	curve.SetExact(true)
+	for i := 0; i <= 20; i++ {
+		percent := float32(i) / 20
+		want := bezierY(0.7, 0, 0.84, 0, float64(percent))
+		if got := curve.CurvePercent(0, percent); math.Abs(float64(got)-want) > 1e-5 {
+			t.Errorf("at %v: got %v, want %v", percent, got, want)
+		}
+	}
+
+	curve.SetStepped(0)
+	if got := curve.CurvePercent(0, 0.9); got != 0 {
+		t.Errorf("got %v for a stepped curve, want 0", got)
+	}
+	curve.SetLinear(0)
+	if got := curve.CurvePercent(0, 0.3); got != 0.3 {
+		t.Errorf("got %v for a linear curve, want 0.3", got)
+	}
+}
+
+func TestCurveChannels(t *testing.T) {
+	curve := NewCurve(3)
+	curve.SetCurve(0, 0.42, 0, 0.58, 1)
+	curve.SetChannelStepped(1, 1)
+	if curve.Channels() != 2 {
+		t.Fatalf("got %d channels, want 2", curve.Channels())
+	}
+	// Giving a channel its own curve keeps the curves it shared.
+	if got, want := curve.ChannelPercent(1, 0, 0.25), curve.CurvePercent(0, 0.25); got != want {
+		t.Errorf("got %v on channel 1, want %v as on channel 0", got, want)
+	}
+	if got := curve.CurvePercent(1, 0.5); got != 0.5 {
+		t.Errorf("got %v on channel 0, want it left linear", got)
+	}
+	if got := curve.ChannelPercent(1, 1, 0.5); got != 0 {
+		t.Errorf("got %v on channel 1, want it stepped", got)
+	}
+	if curve.isStepped(1) || !curve.anyStepped(1) {
+		t.Error("only one channel is stepped")
+	}
+	curve.SetStepped(1)
+	if !curve.isStepped(1) {
+		t.Error("every channel is stepped")
+	}
+}
+
+func TestLoadChannelCurves(t *testing.T) {
+	json := `{
+"skeleton": {"spine": "4.1.20"},
+"bones": [{"name": "root"}],
+"animations": {
+  "move": {
+    "bones": {
+      "root": {
+        "translate": [
+          {"x": 0, "y": 0, "curve": [0.25, 0, 0.75, 10, 0.333333, 3.333333, 0.666667, 6.666667]},
+          {"time": 1, "x": 10, "y": 10}
+        ]
+      }
+    }
+  }
+}
+}`
+	data, err := New(strings.NewReader(json), 1, AtlasAttachmentLoader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, move := data.FindAnimation("move")
+	move.SetExactCurves(true)
+	translate := move.timelines[0].(*TranslateTimeline)
+	if translate.curve.Channels() != 2 {
+		t.Fatalf("got %d channel curves, want x and y apart", translate.curve.Channels())
+	}
+	x, y := translate.valueAt(0.25)
+	if want := float32(bezierY(0.25, 0, 0.75, 1, 0.25) * 10); math.Abs(float64(x-want)) > 0.01 {
+		t.Errorf("got x %v, want %v", x, want)
+	}
+	if math.Abs(float64(y-2.5)) > 0.01 {
+		t.Errorf("got y %v, want 2.5", y)
+	}
+}
+
+func TestCurveKeepsControlPoints(t *testing.T) {
+	curve := NewCurve(3)
+	want := [4]float32{0.123457, 0.654321, 0.7, 1.3}
+	curve.SetCurve(1, want[0], want[1], want[2], want[3])
+	curve.SetChannelCurve(1, 1, want[3], want[2], want[1], want[0])
+	cx1, cy1, cx2, cy2 := bezierPoints(curve.curves, 1)
+	if got := [4]float32{cx1, cy1, cx2, cy2}; got != want {
+		t.Errorf("got control points %v, want %v", got, want)
+	}
+	cx1, cy1, cx2, cy2 = bezierPoints(curve.channel(1), 1)
+	if got := [4]float32{cx1, cy1, cx2, cy2}; got != [4]float32{want[3], want[2], want[1], want[0]} {
+		t.Errorf("got channel control points %v", got)
+	}
+	if curve.CurvePercent(0, 0.5) != 0.5 || curve.isStepped(1) {
+		t.Error("setting one segment changed another")
+	}
+}
@@ -0,0 +1,102 @@
+package spine
+
+// EventData is an event defined in the skeleton, with the default values for
+// each time it is keyed in an animation.
+type EventData struct {
+	name string
+
+	Int       int
+	Float     float32
+	String    string
+	AudioPath string
+	Volume    float32
+	Balance   float32
+}
+
+func NewEventData(name string) *EventData {
+	return &EventData{name: name, Volume: 1}
+}
+
+func (e *EventData) Name() string {
+	return e.name
+}
+
+// Event is an event keyed at a time in an animation, with the values for
+// that key.
+type Event struct {
+	data *EventData
+	Time float32
+
+	Int     int
+	Float   float32
+	String  string
+	Volume  float32
+	Balance float32
+}
+
+// NewEvent returns an event at time with the default values of data.
+func NewEvent(time float32, data *EventData) *Event {
+	return &Event{
+		data:    data,
+		Time:    time,
+		Int:     data.Int,
+		Float:   data.Float,
+		String:  data.String,
+		Volume:  data.Volume,
+		Balance: data.Balance,
+	}
+}
+
+func (e *Event) Data() *EventData {
+	return e.data
+}
+
+// EventTimeline keys events in an animation. Applying it does nothing to the
+// skeleton; a Playback reports the events as its time passes them.
+type EventTimeline struct {
+	frames []float32
+	events []*Event
+}
+
+func NewEventTimeline(l int) *EventTimeline {
+	return &EventTimeline{
+		frames: make([]float32, l),
+		events: make([]*Event, l),
+	}
+}
+
+func (t *EventTimeline) FrameCount() int {
+	return len(t.frames)
+}
+
+func (t *EventTimeline) Frame(index int) *Event {
+	return t.events[index]
+}
+
+func (t *EventTimeline) setFrame(index int, event *Event) {
+	t.frames[index] = event.Time
+	t.events[index] = event
+}
+
+func (t *EventTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+}
+
+// collect appends to fired the events passed when time moves from one time
+// to another, in the order they are passed. The time moved from only counts
+// when inclusive is true, so that consecutive moves report each event once.
+func (t *EventTimeline) collect(from, to float32, inclusive bool, fired []*Event) []*Event {
+	if from <= to {
+		for i, time := range t.frames {
+			if (time > from || inclusive && time == from) && time <= to {
+				fired = append(fired, t.events[i])
+			}
+		}
+		return fired
+	}
+	for i := len(t.frames) - 1; i >= 0; i-- {
+		if time := t.frames[i]; (time < from || inclusive && time == from) && time >= to {
+			fired = append(fired, t.events[i])
+		}
+	}
+	return fired
+}
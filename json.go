@@ -0,0 +1,256 @@
+package spine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LoadError reports a problem with the skeleton JSON, along with the path of
+// the offending value, such as animations.walk.bones.hip.rotate[3].time.
+type LoadError struct {
+	Path string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	msg := strings.TrimPrefix(e.Err.Error(), "spine: ")
+	if e.Path == "" {
+		return "spine: " + msg
+	}
+	return "spine: " + e.Path + ": " + msg
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func loadError(path string, format string, args ...interface{}) error {
+	return &LoadError{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+// jsonObject is a decoded JSON object that remembers where it came from, so
+// that every failed access can report its path, and the order of its keys.
+type jsonObject struct {
+	path   string
+	order  []string
+	values map[string]interface{}
+}
+
+func newJSONObject(path string, value interface{}) (*jsonObject, error) {
+	o, ok := value.(*jsonObject)
+	if !ok {
+		return nil, loadError(path, "expected object, got %s", jsonType(value))
+	}
+	return &jsonObject{path, o.order, o.values}, nil
+}
+
+// decodeJSON reads one JSON value from dec. Objects are decoded as
+// *jsonObject so that they can be walked in file order.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		o := &jsonObject{values: make(map[string]interface{})}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return nil, errors.New("expected object key")
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := o.values[key]; !ok {
+				o.order = append(o.order, key)
+			}
+			o.values[key] = value
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return o, nil
+	case '[':
+		values := make([]interface{}, 0)
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	return nil, errors.New("unexpected " + delim.String())
+}
+
+func (o *jsonObject) pathTo(key string) string {
+	if o.path == "" {
+		return key
+	}
+	return o.path + "." + key
+}
+
+// keys returns the keys of the object in the order they appear in the file.
+func (o *jsonObject) keys() []string {
+	return o.order
+}
+
+func (o *jsonObject) has(key string) bool {
+	value, ok := o.values[key]
+	return ok && value != nil
+}
+
+func (o *jsonObject) value(key string) interface{} {
+	return o.values[key]
+}
+
+// float returns the number at key, or def when the key is absent or null.
+func (o *jsonObject) float(key string, def float32) (float32, error) {
+	value, ok := o.values[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	f, ok := value.(float64)
+	if !ok {
+		return 0, loadError(o.pathTo(key), "expected number, got %s", jsonType(value))
+	}
+	return float32(f), nil
+}
+
+func (o *jsonObject) requiredFloat(key string) (float32, error) {
+	if !o.has(key) {
+		return 0, loadError(o.pathTo(key), "missing required number")
+	}
+	return o.float(key, 0)
+}
+
+// string returns the string at key, or def when the key is absent or null.
+func (o *jsonObject) string(key string, def string) (string, error) {
+	value, ok := o.values[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", loadError(o.pathTo(key), "expected string, got %s", jsonType(value))
+	}
+	return s, nil
+}
+
+func (o *jsonObject) requiredString(key string) (string, error) {
+	if !o.has(key) {
+		return "", loadError(o.pathTo(key), "missing required string")
+	}
+	return o.string(key, "")
+}
+
+// bool returns the boolean at key, or def when the key is absent or null.
+func (o *jsonObject) bool(key string, def bool) (bool, error) {
+	value, ok := o.values[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, loadError(o.pathTo(key), "expected boolean, got %s", jsonType(value))
+	}
+	return b, nil
+}
+
+// object returns the object at key, or nil when the key is absent or null.
+func (o *jsonObject) object(key string) (*jsonObject, error) {
+	if !o.has(key) {
+		return nil, nil
+	}
+	return newJSONObject(o.pathTo(key), o.values[key])
+}
+
+// array returns the array at key, or nil when the key is absent or null.
+func (o *jsonObject) array(key string) (*jsonArray, error) {
+	if !o.has(key) {
+		return nil, nil
+	}
+	return newJSONArray(o.pathTo(key), o.values[key])
+}
+
+type jsonArray struct {
+	path   string
+	values []interface{}
+}
+
+func newJSONArray(path string, value interface{}) (*jsonArray, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, loadError(path, "expected array, got %s", jsonType(value))
+	}
+	return &jsonArray{path, values}, nil
+}
+
+func (a *jsonArray) len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.values)
+}
+
+func (a *jsonArray) pathTo(i int) string {
+	return fmt.Sprintf("%s[%d]", a.path, i)
+}
+
+func (a *jsonArray) object(i int) (*jsonObject, error) {
+	return newJSONObject(a.pathTo(i), a.values[i])
+}
+
+func (a *jsonArray) string(i int) (string, error) {
+	s, ok := a.values[i].(string)
+	if !ok {
+		return "", loadError(a.pathTo(i), "expected string, got %s", jsonType(a.values[i]))
+	}
+	return s, nil
+}
+
+func (a *jsonArray) floats() ([]float32, error) {
+	floats := make([]float32, len(a.values))
+	for i, value := range a.values {
+		f, ok := value.(float64)
+		if !ok {
+			return nil, loadError(a.pathTo(i), "expected number, got %s", jsonType(value))
+		}
+		floats[i] = float32(f)
+	}
+	return floats, nil
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case *jsonObject:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,130 @@
+package spine
+
+import (
+	"fmt"
+)
+
+// Mask selects the bones and slots of a skeleton that an animation may
+// affect, so that one animation can drive part of a skeleton, such as the
+// upper body, while another drives the rest. A mask belongs to the skeleton
+// data it was created for.
+type Mask struct {
+	data  *SkeletonData
+	bones []bool
+	slots []bool
+}
+
+// NewMask returns a mask that includes nothing.
+func NewMask(data *SkeletonData) *Mask {
+	return &Mask{
+		data:  data,
+		bones: make([]bool, len(data.bones)),
+		slots: make([]bool, len(data.slots)),
+	}
+}
+
+// NewBoneMask returns a mask that includes the named bones, all of their
+// descendants and the slots attached to any of them.
+func NewBoneMask(data *SkeletonData, names ...string) (*Mask, error) {
+	m := NewMask(data)
+	for _, name := range names {
+		if err := m.AddSubtree(name); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// AddSubtree includes the named bone, its descendants and their slots.
+func (m *Mask) AddSubtree(name string) error {
+	return m.setSubtree(name, true)
+}
+
+// RemoveSubtree excludes the named bone, its descendants and their slots.
+func (m *Mask) RemoveSubtree(name string) error {
+	return m.setSubtree(name, false)
+}
+
+func (m *Mask) setSubtree(name string, included bool) error {
+	root, _ := m.data.FindBone(name)
+	if root == -1 {
+		return fmt.Errorf("%w: %s", ErrBoneNotFound, name)
+	}
+
+	// Parents always come before their children, so one pass finds the
+	// whole subtree.
+	inSubtree := make([]bool, len(m.data.bones))
+	inSubtree[root] = true
+	for i := root + 1; i < len(m.data.bones); i++ {
+		if parent := m.data.bones[i].parent; parent != nil {
+			p, _ := m.data.FindBone(parent.name)
+			inSubtree[i] = inSubtree[p]
+		}
+	}
+	for i, in := range inSubtree {
+		if in {
+			m.bones[i] = included
+		}
+	}
+	for i, slot := range m.data.slots {
+		b, _ := m.data.FindBone(slot.boneData.name)
+		if inSubtree[b] {
+			m.slots[i] = included
+		}
+	}
+	return nil
+}
+
+// AddSlot includes the named slot without including its bone.
+func (m *Mask) AddSlot(name string) error {
+	return m.setSlot(name, true)
+}
+
+// RemoveSlot excludes the named slot without excluding its bone.
+func (m *Mask) RemoveSlot(name string) error {
+	return m.setSlot(name, false)
+}
+
+func (m *Mask) setSlot(name string, included bool) error {
+	i, _ := m.data.FindSlot(name)
+	if i == -1 {
+		return fmt.Errorf("%w: %s", ErrSlotNotFound, name)
+	}
+	m.slots[i] = included
+	return nil
+}
+
+func (m *Mask) HasBone(index int) bool {
+	return index >= 0 && index < len(m.bones) && m.bones[index]
+}
+
+func (m *Mask) HasSlot(index int) bool {
+	return index >= 0 && index < len(m.slots) && m.slots[index]
+}
+
+// Invert returns a mask that includes exactly what m excludes.
+func (m *Mask) Invert() *Mask {
+	inverted := NewMask(m.data)
+	for i, in := range m.bones {
+		inverted.bones[i] = !in
+	}
+	for i, in := range m.slots {
+		inverted.slots[i] = !in
+	}
+	return inverted
+}
+
+// allows reports whether the timeline may be applied. A nil mask allows
+// everything, as do timelines that key neither a bone nor a slot.
+func (m *Mask) allows(timeline Timeline) bool {
+	if m == nil {
+		return true
+	}
+	switch t := timeline.(type) {
+	case BoneTimeline:
+		return m.HasBone(t.BoneIndex())
+	case SlotTimeline:
+		return m.HasSlot(t.SlotIndex())
+	}
+	return true
+}
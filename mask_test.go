@@ -0,0 +1,87 @@
+package spine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaskSubtrees(t *testing.T) {
+	data := loadTest(t)
+	m, err := NewBoneMask(data, "hip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The head subtree is removed even though its ancestor hip was added.
+	if err := m.RemoveSubtree("head"); err != nil {
+		t.Fatal(err)
+	}
+	bones := []bool{m.HasBone(0), m.HasBone(1), m.HasBone(2), m.HasBone(3)}
+	if bones[0] || !bones[1] || !bones[2] || bones[3] {
+		t.Errorf("got bones root, hip, torso, head included %v", bones)
+	}
+	slots := []bool{m.HasSlot(0), m.HasSlot(1), m.HasSlot(2)}
+	if !slots[0] || slots[1] || slots[2] {
+		t.Errorf("got slots torso, head, hat included %v", slots)
+	}
+
+	inverted := m.Invert()
+	for i := range data.bones {
+		if inverted.HasBone(i) == m.HasBone(i) {
+			t.Errorf("inverting kept bone %d", i)
+		}
+	}
+	for i := range data.slots {
+		if inverted.HasSlot(i) == m.HasSlot(i) {
+			t.Errorf("inverting kept slot %d", i)
+		}
+	}
+	if m.HasBone(-1) || m.HasBone(4) {
+		t.Error("includes bones out of range")
+	}
+
+	if _, err := NewBoneMask(data, "tail"); !errors.Is(err, ErrBoneNotFound) {
+		t.Errorf("got %v, want a missing bone", err)
+	}
+	if err := m.AddSlot("tail"); !errors.Is(err, ErrSlotNotFound) {
+		t.Errorf("got %v, want a missing slot", err)
+	}
+}
+
+func TestMaskedMix(t *testing.T) {
+	data := loadTest(t)
+	walk := NewSkeleton(data).FindAnimation("walk")
+	// Only the root, which is animated separately from the hip and slots.
+	rootOnly := NewMask(data)
+	rootOnly.AddSubtree("root")
+	rootOnly.RemoveSubtree("hip")
+	headSlot := NewMask(data)
+	headSlot.AddSlot("head")
+
+	tests := []struct {
+		name                string
+		mask                *Mask
+		root, hip, headSlot bool
+	}{
+		{"root only", rootOnly, true, false, false},
+		{"inverted", rootOnly.Invert(), false, true, true},
+		{"head slot", headSlot, false, false, true},
+		{"none", nil, true, true, true},
+	}
+	for _, test := range tests {
+		skeleton := NewSkeleton(data)
+		head := skeleton.Slots[1]
+		setupAttachment := head.Attachment
+		walk.MixWith(skeleton, 0.75, false, 1, MixOptions{Mask: test.mask})
+
+		if moved := skeleton.RootBone().X != 0; moved != test.root {
+			t.Errorf("%s: root moved %v, want %v", test.name, moved, test.root)
+		}
+		hip := skeleton.Bones[1]
+		if moved := hip.Rotation != 0 || hip.ScaleX != 1; moved != test.hip {
+			t.Errorf("%s: hip moved %v, want %v", test.name, moved, test.hip)
+		}
+		if changed := head.A != 1 || head.Attachment != setupAttachment; changed != test.headSlot {
+			t.Errorf("%s: head slot changed %v, want %v", test.name, changed, test.headSlot)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package spine
+
+import (
+	"errors"
+	"math"
+)
+
+type PlayMode int
+
+const (
+	// PlayLoop plays forward and starts over at the end.
+	PlayLoop PlayMode = iota
+	// PlayOnce plays forward and stops at the end.
+	PlayOnce
+	// PlayReverse plays backward and starts over at the start.
+	PlayReverse
+	// PlayReverseOnce plays backward and stops at the start.
+	PlayReverseOnce
+	// PlayPingPong plays forward, then backward, and repeats.
+	PlayPingPong
+)
+
+// Playback plays an animation, or part of one, on a skeleton over time. It
+// keeps the state that Animation.Apply leaves to the caller: the play mode
+// and time scale, the events passed and whether the animation has completed.
+// Each skeleton needs its own Playback.
+type Playback struct {
+	animation *Animation
+	Mode      PlayMode
+	// TimeScale multiplies the time passed to Update. Negative values count
+	// as 0; use a reverse mode to play backward.
+	TimeScale float32
+
+	start, end float32
+	// elapsed is the scaled time played since the start, which runs on
+	// across loops and bounces.
+	elapsed  float32
+	started  bool
+	complete bool
+	events   []*Event
+
+	applied     bool
+	appliedTime float32
+
+	eventTimeline       *EventTimeline
+	attachmentTimelines []*AttachmentTimeline
+}
+
+func NewPlayback(animation *Animation) *Playback {
+	p := &Playback{
+		animation: animation,
+		TimeScale: 1,
+		end:       animation.duration,
+	}
+	for _, timeline := range animation.timelines {
+		switch t := timeline.(type) {
+		case *EventTimeline:
+			p.eventTimeline = t
+		case *AttachmentTimeline:
+			p.attachmentTimelines = append(p.attachmentTimelines, t)
+		}
+	}
+	return p
+}
+
+func (p *Playback) Animation() *Animation {
+	return p.animation
+}
+
+// Range returns the part of the animation that is played.
+func (p *Playback) Range() (start, end float32) {
+	return p.start, p.end
+}
+
+// SetRange plays only the part of the animation between start and end, and
+// starts playing it over. The range must lie within the animation.
+func (p *Playback) SetRange(start, end float32) error {
+	if start < 0 || end <= start || end > p.animation.duration {
+		return errors.New("spine: invalid playback range")
+	}
+	p.start, p.end = start, end
+	p.Reset()
+	return nil
+}
+
+// Reset starts playing over. Events at the first time are reported by the
+// next Update.
+func (p *Playback) Reset() {
+	p.elapsed = 0
+	p.started = false
+	p.complete = false
+	p.events = p.events[:0]
+}
+
+// Time returns the current time in the animation.
+func (p *Playback) Time() float32 {
+	return p.timeAt(p.elapsed)
+}
+
+// Complete reports whether the last Update reached the end of a play-once
+// mode, or finished a cycle of a looping mode. A ping-pong cycle is there
+// and back again.
+func (p *Playback) Complete() bool {
+	return p.complete
+}
+
+// Events returns the events passed by the last Update, in the order they
+// were passed. The slice is reused by the next Update.
+func (p *Playback) Events() []*Event {
+	return p.events
+}
+
+func (p *Playback) once() bool {
+	return p.Mode == PlayOnce || p.Mode == PlayReverseOnce
+}
+
+// Update advances the playback by dt seconds, scaled by TimeScale.
+func (p *Playback) Update(dt float32) {
+	p.events = p.events[:0]
+	p.complete = false
+	if p.TimeScale > 0 {
+		dt *= p.TimeScale
+	} else {
+		dt = 0
+	}
+
+	length := p.end - p.start
+	from := p.elapsed
+	to := from + dt
+	if p.once() && to > length {
+		to = length
+	}
+	if p.once() {
+		p.complete = to >= length && (from < length || !p.started)
+	} else if length > 0 {
+		cycle := length
+		if p.Mode == PlayPingPong {
+			cycle *= 2
+		}
+		p.complete = math.Floor(float64(to/cycle)) > math.Floor(float64(from/cycle))
+	}
+	p.elapsed = to
+
+	if p.eventTimeline != nil && (to > from || !p.started) {
+		p.collect(from, to)
+	}
+	p.started = true
+}
+
+// collect reports the events between two elapsed times, one loop or bounce
+// at a time.
+func (p *Playback) collect(from, to float32) {
+	length := p.end - p.start
+	if length <= 0 {
+		p.events = p.eventTimeline.collect(p.start, p.start, !p.started, p.events)
+		return
+	}
+	segment := math.Floor(float64(from / length))
+	if p.once() {
+		segment = 0
+	}
+	// A loop starts over at a time that has not been passed yet, while a
+	// bounce turns around at the time just passed.
+	segmentStart := float32(segment) * length
+	inclusive := !p.started || p.Mode != PlayPingPong && from > 0 && from == segmentStart
+	for {
+		a := float32(math.Max(float64(from), float64(segmentStart)))
+		b := float32(math.Min(float64(to), float64(segmentStart+length)))
+		p.events = p.eventTimeline.collect(p.segmentTime(segment, a-segmentStart), p.segmentTime(segment, b-segmentStart), inclusive, p.events)
+		if to <= segmentStart+length {
+			return
+		}
+		segment++
+		segmentStart += length
+		inclusive = p.Mode != PlayPingPong
+	}
+}
+
+// timeAt returns the animation time after playing for elapsed seconds.
+func (p *Playback) timeAt(elapsed float32) float32 {
+	length := p.end - p.start
+	if length <= 0 {
+		return p.start
+	}
+	if p.once() {
+		if elapsed > length {
+			elapsed = length
+		}
+		return p.segmentTime(0, elapsed)
+	}
+	segment := math.Floor(float64(elapsed / length))
+	return p.segmentTime(segment, elapsed-float32(segment)*length)
+}
+
+// segmentTime returns the animation time at offset into a loop or bounce.
+func (p *Playback) segmentTime(segment float64, offset float32) float32 {
+	reverse := p.Mode == PlayReverse || p.Mode == PlayReverseOnce ||
+		p.Mode == PlayPingPong && math.Mod(segment, 2) == 1
+	if reverse {
+		return p.end - offset
+	}
+	return p.start + offset
+}
+
+func (p *Playback) Apply(skeleton *Skeleton) {
+	p.MixWith(skeleton, 1, MixOptions{})
+}
+
+func (p *Playback) Mix(skeleton *Skeleton, alpha float32) {
+	p.MixWith(skeleton, alpha, MixOptions{})
+}
+
+// MixWith mixes the animation at the current time into the skeleton. When
+// time has gone back to before the first key of an attachment timeline,
+// such as when looping or playing backward, the slot goes back to its setup
+// pose attachment.
+func (p *Playback) MixWith(skeleton *Skeleton, alpha float32, options MixOptions) {
+	time := p.Time()
+	if p.applied {
+		for _, t := range p.attachmentTimelines {
+			first := t.frames[0]
+			if time < first && p.appliedTime >= first && options.Mask.allows(t) {
+				t.setToSetupPose(skeleton)
+			}
+		}
+	}
+	p.animation.MixWith(skeleton, time, false, alpha, options)
+	p.applied = true
+	p.appliedTime = time
+}
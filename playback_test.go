@@ -0,0 +1,79 @@
+package spine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// eventNames lists the passed events as name@time.
+func eventNames(p *Playback) string {
+	var names []string
+	for _, e := range p.Events() {
+		names = append(names, fmt.Sprintf("%s@%g", e.Data().Name(), e.Time))
+	}
+	return strings.Join(names, " ")
+}
+
+func TestPlaybackEvents(t *testing.T) {
+	_, walk := loadTest(t).FindAnimation("walk")
+	tests := []struct {
+		mode    PlayMode
+		updates []float32
+		events  []string
+	}{
+		{PlayLoop, []float32{0.25, 0.5, 0.5, 1}, []string{"step@0", "shout@0.5", "step@1 step@0", "shout@0.5 step@1 step@0"}},
+		{PlayOnce, []float32{0.25, 1, 1}, []string{"step@0", "shout@0.5 step@1", ""}},
+		{PlayReverse, []float32{0.25, 0.5, 0.5}, []string{"step@1", "shout@0.5", "step@0 step@1"}},
+		{PlayPingPong, []float32{0.75, 0.5, 0.5}, []string{"step@0 shout@0.5", "step@1", "shout@0.5"}},
+	}
+	for _, test := range tests {
+		p := NewPlayback(walk)
+		p.Mode = test.mode
+		for i, dt := range test.updates {
+			p.Update(dt)
+			if got := eventNames(p); got != test.events[i] {
+				t.Errorf("mode %d, update %d: got events %q, want %q", test.mode, i, got, test.events[i])
+			}
+		}
+	}
+}
+
+func TestPlaybackComplete(t *testing.T) {
+	_, walk := loadTest(t).FindAnimation("walk")
+	p := NewPlayback(walk)
+	p.Mode = PlayOnce
+	for i, want := range []bool{false, true, false} {
+		p.Update(0.6)
+		if p.Complete() != want {
+			t.Errorf("update %d: got complete %v, want %v", i, p.Complete(), want)
+		}
+	}
+	if p.Time() != 1 {
+		t.Errorf("got time %v after playing once, want 1", p.Time())
+	}
+}
+
+func TestPlaybackRange(t *testing.T) {
+	_, walk := loadTest(t).FindAnimation("walk")
+	p := NewPlayback(walk)
+	if err := p.SetRange(0.5, 0.25); err == nil {
+		t.Error("set an empty range")
+	}
+	if err := p.SetRange(0.5, 1.5); err == nil {
+		t.Error("set a range past the end of the animation")
+	}
+	if start, end := p.Range(); start != 0 || end != 1 {
+		t.Errorf("a rejected range changed the range to %v, %v", start, end)
+	}
+	if err := p.SetRange(0.25, 0.75); err != nil {
+		t.Fatal(err)
+	}
+	p.Update(0.5)
+	if got := eventNames(p); got != "shout@0.5" {
+		t.Errorf("got events %q, want only the shout inside the range", got)
+	}
+	if !p.Complete() || p.Time() != 0.25 {
+		t.Errorf("got complete %v at time %v, want a finished cycle back at 0.25", p.Complete(), p.Time())
+	}
+}
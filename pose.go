@@ -0,0 +1,323 @@
+package spine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type BonePose struct {
+	X, Y           float32
+	Rotation       float32
+	ScaleX, ScaleY float32
+}
+
+type SlotPose struct {
+	R, G, B, A float32
+	// Attachment is the name the attachment is stored under in the skin, or
+	// empty when the slot has none.
+	Attachment string
+
+	attachment Attachment
+}
+
+// Pose is a snapshot of the animated state of a skeleton: the local bone
+// transforms, slot colors and attachments, and the draw order as slot
+// indices.
+type Pose struct {
+	Bones     []BonePose
+	Slots     []SlotPose
+	DrawOrder []int
+}
+
+// CapturePose returns the current pose of the skeleton.
+func CapturePose(skeleton *Skeleton) *Pose {
+	p := new(Pose)
+	p.Capture(skeleton)
+	return p
+}
+
+// Capture stores the current pose of the skeleton in p, reusing its slices.
+func (p *Pose) Capture(skeleton *Skeleton) {
+	p.Bones = p.Bones[:0]
+	for _, bone := range skeleton.Bones {
+		p.Bones = append(p.Bones, BonePose{bone.X, bone.Y, bone.Rotation, bone.ScaleX, bone.ScaleY})
+	}
+
+	p.Slots = p.Slots[:0]
+	for i, slot := range skeleton.Slots {
+		p.Slots = append(p.Slots, SlotPose{
+			R:          slot.R,
+			G:          slot.G,
+			B:          slot.B,
+			A:          slot.A,
+			Attachment: skeleton.attachmentName(i, slot.Attachment),
+			attachment: slot.Attachment,
+		})
+	}
+
+	p.DrawOrder = p.DrawOrder[:0]
+	for _, slot := range skeleton.DrawOrder {
+		i, _ := skeleton.data.FindSlot(slot.data.name)
+		p.DrawOrder = append(p.DrawOrder, i)
+	}
+}
+
+// Restore poses the skeleton as captured. Attachments of a decoded pose are
+// looked up by name through the skeleton's skins.
+func (p *Pose) Restore(skeleton *Skeleton) error {
+	if len(p.Bones) != len(skeleton.Bones) || len(p.Slots) != len(skeleton.Slots) || len(p.DrawOrder) != len(skeleton.DrawOrder) {
+		return errors.New("spine: pose does not match skeleton")
+	}
+	for _, i := range p.DrawOrder {
+		if i < 0 || i >= len(skeleton.Slots) {
+			return errors.New("spine: pose does not match skeleton")
+		}
+	}
+	attachments := make([]Attachment, len(p.Slots))
+	for i, sp := range p.Slots {
+		attachment := sp.attachment
+		if attachment == nil && sp.Attachment != "" {
+			if attachment = skeleton.AttachmentBySlotIndex(i, sp.Attachment); attachment == nil {
+				return fmt.Errorf("%w: %s, for slot: %s", ErrAttachmentNotFound, sp.Attachment, skeleton.Slots[i].data.name)
+			}
+		}
+		attachments[i] = attachment
+	}
+
+	for i, bp := range p.Bones {
+		bone := skeleton.Bones[i]
+		bone.X = bp.X
+		bone.Y = bp.Y
+		bone.Rotation = bp.Rotation
+		bone.ScaleX = bp.ScaleX
+		bone.ScaleY = bp.ScaleY
+	}
+	for i, sp := range p.Slots {
+		slot := skeleton.Slots[i]
+		slot.R, slot.G, slot.B, slot.A = sp.R, sp.G, sp.B, sp.A
+		if slot.Attachment != attachments[i] {
+			slot.SetAttachment(attachments[i])
+			slot.nameAttachment(attachments[i], sp.Attachment)
+		}
+	}
+	for i, slotIndex := range p.DrawOrder {
+		skeleton.DrawOrder[i] = skeleton.Slots[slotIndex]
+	}
+	return nil
+}
+
+// Lerp stores in p the pose between a and b at t, from 0 to 1. Rotations
+// take the shortest way around, and attachments and draw order switch from
+// a to b halfway.
+func (p *Pose) Lerp(a, b *Pose, t float32) error {
+	if len(a.Bones) != len(b.Bones) || len(a.Slots) != len(b.Slots) || len(a.DrawOrder) != len(b.DrawOrder) {
+		return errors.New("spine: poses do not match")
+	}
+
+	// a or b may be p itself, so each element is read before it is written.
+	bones := resizeBonePoses(p.Bones, len(a.Bones))
+	for i := range bones {
+		ab, bb := a.Bones[i], b.Bones[i]
+		amount := bb.Rotation - ab.Rotation
+		for amount > 180 {
+			amount -= 360
+		}
+		for amount < -180 {
+			amount += 360
+		}
+		bones[i] = BonePose{
+			X:        ab.X + (bb.X-ab.X)*t,
+			Y:        ab.Y + (bb.Y-ab.Y)*t,
+			Rotation: ab.Rotation + amount*t,
+			ScaleX:   ab.ScaleX + (bb.ScaleX-ab.ScaleX)*t,
+			ScaleY:   ab.ScaleY + (bb.ScaleY-ab.ScaleY)*t,
+		}
+	}
+
+	nearest := a
+	if t >= 0.5 {
+		nearest = b
+	}
+	slots := resizeSlotPoses(p.Slots, len(a.Slots))
+	for i := range slots {
+		as, bs := a.Slots[i], b.Slots[i]
+		slots[i] = SlotPose{
+			R:          as.R + (bs.R-as.R)*t,
+			G:          as.G + (bs.G-as.G)*t,
+			B:          as.B + (bs.B-as.B)*t,
+			A:          as.A + (bs.A-as.A)*t,
+			Attachment: nearest.Slots[i].Attachment,
+			attachment: nearest.Slots[i].attachment,
+		}
+	}
+
+	if nearest != p {
+		p.DrawOrder = append(p.DrawOrder[:0], nearest.DrawOrder...)
+	}
+	p.Bones = bones
+	p.Slots = slots
+	return nil
+}
+
+func resizeBonePoses(bones []BonePose, n int) []BonePose {
+	if cap(bones) < n {
+		return make([]BonePose, n)
+	}
+	return bones[:n]
+}
+
+func resizeSlotPoses(slots []SlotPose, n int) []SlotPose {
+	if cap(slots) < n {
+		return make([]SlotPose, n)
+	}
+	return slots[:n]
+}
+
+const poseEncodingVersion = 1
+
+// MarshalBinary encodes the pose. Attachments are stored by name.
+func (p *Pose) MarshalBinary() ([]byte, error) {
+	data := []byte{poseEncodingVersion}
+	data = binary.AppendUvarint(data, uint64(len(p.Bones)))
+	for _, bp := range p.Bones {
+		data = appendFloats(data, bp.X, bp.Y, bp.Rotation, bp.ScaleX, bp.ScaleY)
+	}
+	data = binary.AppendUvarint(data, uint64(len(p.Slots)))
+	for _, sp := range p.Slots {
+		data = appendFloats(data, sp.R, sp.G, sp.B, sp.A)
+		data = binary.AppendUvarint(data, uint64(len(sp.Attachment)))
+		data = append(data, sp.Attachment...)
+	}
+	data = binary.AppendUvarint(data, uint64(len(p.DrawOrder)))
+	for _, i := range p.DrawOrder {
+		data = binary.AppendUvarint(data, uint64(i))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a pose encoded by MarshalBinary.
+func (p *Pose) UnmarshalBinary(data []byte) error {
+	d := binaryDecoder{data: data, truncated: errPoseTruncated}
+	if version := d.byte(); version != poseEncodingVersion && d.err == nil {
+		return errors.New("spine: unknown pose encoding version")
+	}
+
+	bones := make([]BonePose, d.count(20))
+	for i := range bones {
+		bones[i] = BonePose{d.float(), d.float(), d.float(), d.float(), d.float()}
+	}
+	slots := make([]SlotPose, d.count(17))
+	for i := range slots {
+		slots[i] = SlotPose{R: d.float(), G: d.float(), B: d.float(), A: d.float()}
+		slots[i].Attachment = d.string()
+	}
+	drawOrder := make([]int, d.count(1))
+	for i := range drawOrder {
+		drawOrder[i] = d.count(0)
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	p.Bones = bones
+	p.Slots = slots
+	p.DrawOrder = drawOrder
+	return nil
+}
+
+func appendFloats(data []byte, values ...float32) []byte {
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	}
+	return data
+}
+
+var errPoseTruncated = errors.New("spine: truncated pose data")
+
+// binaryDecoder reads values from data, remembering the first error so that
+// callers can check once at the end. Running out of data sets err to
+// truncated.
+type binaryDecoder struct {
+	data      []byte
+	err       error
+	truncated error
+}
+
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil || len(d.data) < 1 {
+		d.err = d.truncated
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *binaryDecoder) float() float32 {
+	if d.err != nil || len(d.data) < 4 {
+		d.err = d.truncated
+		return 0
+	}
+	v := math.Float32frombits(binary.LittleEndian.Uint32(d.data))
+	d.data = d.data[4:]
+	return v
+}
+
+// count reads a length or index. A length of items taking at least minSize
+// bytes each is checked against the remaining data before it is used to
+// allocate.
+func (d *binaryDecoder) count(minSize int) int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v > math.MaxInt32 {
+		d.err = d.truncated
+		return 0
+	}
+	d.data = d.data[n:]
+	if minSize > 0 && v > uint64(len(d.data)/minSize) {
+		d.err = d.truncated
+		return 0
+	}
+	return int(v)
+}
+
+func (d *binaryDecoder) bytes(n int) []byte {
+	if d.err != nil || len(d.data) < n {
+		d.err = d.truncated
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *binaryDecoder) uint16() uint16 {
+	if d.err != nil || len(d.data) < 2 {
+		d.err = d.truncated
+		return 0
+	}
+	v := binary.LittleEndian.Uint16(d.data)
+	d.data = d.data[2:]
+	return v
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = d.truncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) string() string {
+	return string(d.bytes(d.count(1)))
+}
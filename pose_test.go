@@ -0,0 +1,88 @@
+package spine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPoseBinaryRoundTrip(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	walk := skeleton.FindAnimation("walk")
+	walk.Apply(skeleton, 0.3, false)
+	pose := CapturePose(skeleton)
+
+	data, err := pose.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Pose
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	walk.Apply(skeleton, 0.9, false)
+	if err := decoded.Restore(skeleton); err != nil {
+		t.Fatal(err)
+	}
+	restored := CapturePose(skeleton)
+	for i, bone := range pose.Bones {
+		if restored.Bones[i] != bone {
+			t.Errorf("bone %d: got %+v, want %+v", i, restored.Bones[i], bone)
+		}
+	}
+	for i, slot := range pose.Slots {
+		got := restored.Slots[i]
+		if got.R != slot.R || got.G != slot.G || got.B != slot.B || got.A != slot.A || got.Attachment != slot.Attachment {
+			t.Errorf("slot %d: got %+v, want %+v", i, got, slot)
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		var truncated Pose
+		if truncated.UnmarshalBinary(data[:i]) == nil {
+			t.Fatalf("accepted data truncated to %d bytes", i)
+		}
+	}
+}
+
+func TestPoseRestoreMissingAttachment(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	pose := CapturePose(skeleton)
+	data, _ := pose.MarshalBinary()
+	var decoded Pose
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	decoded.Slots[2].Attachment = "crown"
+	err := decoded.Restore(skeleton)
+	if !errors.Is(err, ErrAttachmentNotFound) || err.Error() != "spine: attachment not found: crown, for slot: hat" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestPoseCaptureAttachmentKey(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skin := NewSkin("aliased")
+	skin.AddAttachment(1, "alias", skeleton.Slots[1].Attachment)
+	skeleton.SetSkin(skin)
+	skeleton.SetAttachment("head", "alias")
+
+	pose := CapturePose(skeleton)
+	if pose.Slots[1].Attachment != "alias" {
+		t.Fatalf("captured attachment %q, want %q", pose.Slots[1].Attachment, "alias")
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		pose.Capture(skeleton)
+	})
+	if allocs != 0 {
+		t.Fatalf("Capture allocated %v times", allocs)
+	}
+}
+
+func TestPoseLerpMismatch(t *testing.T) {
+	pose := CapturePose(NewSkeleton(loadTest(t)))
+	short := &Pose{Bones: pose.Bones[:2], Slots: pose.Slots, DrawOrder: pose.DrawOrder}
+	if err := new(Pose).Lerp(pose, short, 0.5); err == nil {
+		t.Error("blended poses of different skeletons")
+	}
+}
@@ -0,0 +1,290 @@
+package spine
+
+import (
+	"errors"
+	"math"
+)
+
+// ReduceStats reports how much Reduce shrank an animation. Sizes count the
+// frame and curve data of the timelines in bytes.
+type ReduceStats struct {
+	KeysBefore, KeysAfter   int
+	BytesBefore, BytesAfter int
+}
+
+// reduceChecks is how many points are checked inside each original span
+// between keys, in addition to the keys themselves.
+const reduceChecks = 8
+
+// Reduce returns a copy of the animation with the rotate, translate, scale
+// and color keys removed that interpolating between the keys either side of
+// them reproduces within tolerance. The spans left are linear where that is
+// close enough, and otherwise get a Bezier curve fitted to the keys removed.
+// Spans where any channel is stepped are kept as they are. Other timelines
+// are shared with the original.
+func Reduce(animation *Animation, tolerance float32) (*Animation, ReduceStats, error) {
+	var stats ReduceStats
+	if tolerance < 0 {
+		return nil, stats, errors.New("spine: reduce tolerance must not be negative")
+	}
+	timelines := make([]Timeline, len(animation.timelines))
+	for i, timeline := range animation.timelines {
+		timelines[i] = reduceTimeline(timeline, tolerance)
+		keys, bytes := timelineSize(timeline)
+		stats.KeysBefore += keys
+		stats.BytesBefore += bytes
+		keys, bytes = timelineSize(timelines[i])
+		stats.KeysAfter += keys
+		stats.BytesAfter += bytes
+	}
+	return NewAnimation(animation.name, timelines, animation.duration), stats, nil
+}
+
+func timelineSize(timeline Timeline) (keys, bytes int) {
+	switch t := timeline.(type) {
+	case *RotateTimeline:
+		return t.FrameCount(), (len(t.frames) + len(t.curve.curves)*t.curve.Channels()) * 4
+	case *TranslateTimeline:
+		return t.FrameCount(), (len(t.frames) + len(t.curve.curves)*t.curve.Channels()) * 4
+	case *ScaleTimeline:
+		return t.FrameCount(), (len(t.frames) + len(t.curve.curves)*t.curve.Channels()) * 4
+	case *ColorTimeline:
+		return t.FrameCount(), (len(t.frames) + len(t.curve.curves)*t.curve.Channels()) * 4
+	}
+	return 0, 0
+}
+
+func reduceTimeline(timeline Timeline, tolerance float32) Timeline {
+	switch t := timeline.(type) {
+	case *RotateTimeline:
+		r := newKeyReducer(t.frames, 2, t.curve, tolerance, func(time float32, v []float32) {
+			v[0] = t.totalAngle(time)
+		})
+		// Rotations take the shorter way between keys, so no span may turn
+		// more than half way round.
+		r.maxDelta = 180
+		keys := r.reduce()
+		reduced := NewRotateTimeline(len(keys))
+		reduced.boneIndex = t.boneIndex
+		for i, k := range keys {
+			reduced.setFrame(i, r.times[k], r.values[k])
+		}
+		r.setCurves(reduced.curve)
+		return reduced
+	case *TranslateTimeline:
+		r := newKeyReducer(t.frames, 3, t.curve, tolerance, func(time float32, v []float32) {
+			v[0], v[1] = t.valueAt(time)
+		})
+		keys := r.reduce()
+		reduced := NewTranslateTimeline(len(keys))
+		reduced.boneIndex = t.boneIndex
+		for i, k := range keys {
+			reduced.setFrame(i, r.times[k], r.values[k*2], r.values[k*2+1])
+		}
+		r.setCurves(reduced.curve)
+		return reduced
+	case *ScaleTimeline:
+		r := newKeyReducer(t.frames, 3, t.curve, tolerance, func(time float32, v []float32) {
+			v[0], v[1] = t.valueAt(time)
+		})
+		keys := r.reduce()
+		reduced := NewScaleTimeline(len(keys))
+		reduced.boneIndex = t.boneIndex
+		for i, k := range keys {
+			reduced.setFrame(i, r.times[k], r.values[k*2], r.values[k*2+1])
+		}
+		r.setCurves(reduced.curve)
+		return reduced
+	case *ColorTimeline:
+		r := newKeyReducer(t.frames, 5, t.curve, tolerance, func(time float32, v []float32) {
+			v[0], v[1], v[2], v[3] = t.valueAt(time)
+		})
+		keys := r.reduce()
+		reduced := NewColorTimeline(len(keys))
+		reduced.slotIndex = t.slotIndex
+		for i, k := range keys {
+			v := r.values[k*4 : k*4+4]
+			reduced.setFrame(i, r.times[k], v[0], v[1], v[2], v[3])
+		}
+		r.setCurves(reduced.curve)
+		return reduced
+	}
+	return timeline
+}
+
+// keyReducer removes keys from one timeline, checking every candidate span
+// against the original timeline.
+type keyReducer struct {
+	channels  int
+	times     []float32
+	values    []float32
+	curve     *Curve
+	value     func(time float32, values []float32)
+	tolerance float32
+	// maxDelta limits how much a channel may change over a span, if not 0.
+	maxDelta float32
+
+	// Result: each span between the keys kept.
+	spans []reducedSpan
+
+	expected, scratch []float32
+}
+
+func newKeyReducer(frames []float32, stride int, curve *Curve, tolerance float32, value func(time float32, values []float32)) *keyReducer {
+	channels := stride - 1
+	r := &keyReducer{
+		channels:  channels,
+		curve:     curve,
+		value:     value,
+		tolerance: tolerance,
+		scratch:   make([]float32, channels),
+	}
+	for i := 0; i < len(frames); i += stride {
+		r.times = append(r.times, frames[i])
+	}
+	r.values = make([]float32, len(r.times)*channels)
+	for i, time := range r.times {
+		value(time, r.values[i*channels:(i+1)*channels])
+	}
+	return r
+}
+
+// reducedSpan is a span kept as the original span from a key to the next,
+// or one with a curve fitted to it, which all channels share.
+type reducedSpan struct {
+	original int
+	curve    [curveSegment]float32
+}
+
+// reduce returns the indices of the keys to keep, extending each span for
+// as long as a curve can still be found that fits it.
+func (r *keyReducer) reduce() []int {
+	n := len(r.times)
+	keys := []int{0}
+	r.spans = r.spans[:0]
+	for a := 0; a < n-1; {
+		end, span := a+1, reducedSpan{original: a}
+		if !r.curve.anyStepped(a) {
+			for b := a + 2; b < n && !r.curve.anyStepped(b-1); b++ {
+				fitted, ok := r.fit(a, b)
+				if !ok {
+					break
+				}
+				end, span = b, reducedSpan{original: -1, curve: fitted}
+			}
+		}
+		keys = append(keys, end)
+		r.spans = append(r.spans, span)
+		a = end
+	}
+	return keys
+}
+
+func (r *keyReducer) setCurves(curve *Curve) {
+	curve.exact = r.curve.exact
+	for _, span := range r.spans {
+		if span.original != -1 {
+			curve.split(r.curve.Channels())
+			break
+		}
+	}
+	for i, span := range r.spans {
+		for channel := 0; channel < curve.Channels(); channel++ {
+			if span.original == -1 {
+				copy(curve.channel(channel)[i*curveSegment:(i+1)*curveSegment], span.curve[:])
+			} else {
+				copy(curve.channel(channel)[i*curveSegment:(i+1)*curveSegment], r.curve.channel(channel)[span.original*curveSegment:(span.original+1)*curveSegment])
+			}
+		}
+	}
+}
+
+// fit returns a curve for the span from key a to key b that reproduces the
+// original timeline within tolerance, trying linear first.
+func (r *keyReducer) fit(a, b int) ([curveSegment]float32, bool) {
+	if r.maxDelta > 0 {
+		for c := 0; c < r.channels; c++ {
+			if math.Abs(float64(r.values[b*r.channels+c]-r.values[a*r.channels+c])) > float64(r.maxDelta) {
+				return [curveSegment]float32{}, false
+			}
+		}
+	}
+
+	// The original values to check against: every key in the span and
+	// points between them.
+	var checks []float32
+	r.expected = r.expected[:0]
+	for k := a; k < b; k++ {
+		for i := 0; i < reduceChecks; i++ {
+			time := r.times[k] + (r.times[k+1]-r.times[k])*float32(i)/reduceChecks
+			r.value(time, r.scratch)
+			checks = append(checks, time)
+			r.expected = append(r.expected, r.scratch...)
+		}
+	}
+
+	var linear [curveSegment]float32
+	if r.fits(a, b, checks, linear) {
+		return linear, true
+	}
+	bezier, ok := r.fitBezier(a, b, checks)
+	if ok && r.fits(a, b, checks, bezier) {
+		return bezier, true
+	}
+	return [curveSegment]float32{}, false
+}
+
+// fits reports whether the span with the given curve is within tolerance of
+// the expected values at every check.
+func (r *keyReducer) fits(a, b int, checks []float32, curve [curveSegment]float32) bool {
+	c := &Curve{curves: curve[:]}
+	duration := r.times[b] - r.times[a]
+	for i, time := range checks {
+		percent := c.CurvePercent(0, (time-r.times[a])/duration)
+		for ch := 0; ch < r.channels; ch++ {
+			v1, v2 := r.values[a*r.channels+ch], r.values[b*r.channels+ch]
+			if math.Abs(float64(v1+(v2-v1)*percent-r.expected[i*r.channels+ch])) > float64(r.tolerance) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fitBezier fits a Bezier curve to the checks by least squares. The x
+// control points are fixed at 1/3 and 2/3, which makes x move evenly, so the
+// error is linear in the two y control points and all channels share them.
+func (r *keyReducer) fitBezier(a, b int, checks []float32) ([curveSegment]float32, bool) {
+	var s11, s12, s22, t1, t2 float64
+	duration := r.times[b] - r.times[a]
+	for i, time := range checks {
+		x := float64((time - r.times[a]) / duration)
+		b1 := 3 * (1 - x) * (1 - x) * x
+		b2 := 3 * (1 - x) * x * x
+		for ch := 0; ch < r.channels; ch++ {
+			v1, v2 := float64(r.values[a*r.channels+ch]), float64(r.values[b*r.channels+ch])
+			delta := v2 - v1
+			target := float64(r.expected[i*r.channels+ch]) - v1 - delta*x*x*x
+			s11 += delta * delta * b1 * b1
+			s12 += delta * delta * b1 * b2
+			s22 += delta * delta * b2 * b2
+			t1 += delta * b1 * target
+			t2 += delta * b2 * target
+		}
+	}
+	det := s11*s22 - s12*s12
+	if math.Abs(det) < 1e-12 {
+		return [curveSegment]float32{}, false
+	}
+	cy1 := float32((t1*s22 - t2*s12) / det)
+	cy2 := float32((s11*t2 - s12*t1) / det)
+
+	var curve [curveSegment]float32
+	c := &Curve{curves: curve[:]}
+	c.SetCurve(0, 1.0/3, cy1, 2.0/3, cy2)
+	// A curve that evaluates as linear or stepped cannot be stored.
+	if curve[0] == 0 || curve[0] == -1 {
+		return curve, false
+	}
+	return curve, true
+}
@@ -0,0 +1,156 @@
+package spine
+
+import "math"
+
+// Retarget returns a copy of the animation for a skeleton with a similar
+// rig. Bone timelines go to the target bone that boneMap gives for the
+// source bone's name, or to the target bone with the same name when the
+// source bone is not in boneMap; map a bone to "" to leave it out. Slot
+// timelines and events go to the target's slot and event of the same name.
+//
+// Timelines key offsets from the setup pose, so each bone keeps its own
+// setup pose and moves the same way from it. Translations are scaled by the
+// ratio of the target bone's length to the source bone's. Bones without a
+// length in either skeleton, such as the root, use the overall scale of the
+// target: the ratio of the summed lengths of the mapped bones that have one.
+// When the parents of the two bones are turned differently in the setup
+// pose, translations are turned to move the same way in the world.
+//
+// The timelines that could not be mapped are returned with the animation.
+func Retarget(animation *Animation, source, target *SkeletonData, boneMap map[string]string) (*Animation, []Timeline) {
+	r := &retargeter{source: source, target: target, boneMap: boneMap, scale: 1}
+	var sourceLength, targetLength float32
+	for i, bone := range source.bones {
+		if j := r.bone(i); j != -1 && bone.Length > 0 && target.bones[j].Length > 0 {
+			sourceLength += bone.Length
+			targetLength += target.bones[j].Length
+		}
+	}
+	if sourceLength > 0 {
+		r.scale = targetLength / sourceLength
+	}
+
+	var timelines, unmapped []Timeline
+	for _, timeline := range animation.timelines {
+		if t := r.timeline(timeline); t != nil {
+			timelines = append(timelines, t)
+		} else {
+			unmapped = append(unmapped, timeline)
+		}
+	}
+	return NewAnimation(animation.name, timelines, animation.duration), unmapped
+}
+
+type retargeter struct {
+	source, target *SkeletonData
+	boneMap        map[string]string
+	// scale is the overall size of the target relative to the source.
+	scale float32
+}
+
+func (r *retargeter) timeline(timeline Timeline) Timeline {
+	source, target := r.source, r.target
+	switch t := timeline.(type) {
+	case *RotateTimeline:
+		boneIndex := r.bone(t.boneIndex)
+		if boneIndex == -1 {
+			return nil
+		}
+		retargeted := NewRotateTimeline(t.FrameCount())
+		retargeted.boneIndex = boneIndex
+		copy(retargeted.frames, t.frames)
+		retargeted.curve = t.curve.clone()
+		return retargeted
+	case *TranslateTimeline:
+		boneIndex := r.bone(t.boneIndex)
+		if boneIndex == -1 {
+			return nil
+		}
+		sourceBone, targetBone := source.bones[t.boneIndex], target.bones[boneIndex]
+		scale := r.scale
+		if sourceBone.Length > 0 && targetBone.Length > 0 {
+			scale = targetBone.Length / sourceBone.Length
+		}
+		// Offsets are in the parent's frame, so they are turned from the
+		// source parent's setup rotation to the target parent's.
+		turn := float64(setupWorldRotation(sourceBone.parent)-setupWorldRotation(targetBone.parent)) * math.Pi / 180
+		cos, sin := float32(math.Cos(turn))*scale, float32(math.Sin(turn))*scale
+		retargeted := NewTranslateTimeline(t.FrameCount())
+		retargeted.boneIndex = boneIndex
+		for i := 0; i < t.FrameCount(); i++ {
+			time, x, y := t.Frame(i)
+			retargeted.setFrame(i, time, x*cos-y*sin, x*sin+y*cos)
+		}
+		retargeted.curve = t.curve.clone()
+		return retargeted
+	case *ScaleTimeline:
+		boneIndex := r.bone(t.boneIndex)
+		if boneIndex == -1 {
+			return nil
+		}
+		retargeted := NewScaleTimeline(t.FrameCount())
+		retargeted.boneIndex = boneIndex
+		copy(retargeted.frames, t.frames)
+		retargeted.curve = t.curve.clone()
+		return retargeted
+	case *ColorTimeline:
+		slotIndex, _ := target.FindSlot(source.slots[t.slotIndex].name)
+		if slotIndex == -1 {
+			return nil
+		}
+		retargeted := NewColorTimeline(t.FrameCount())
+		retargeted.slotIndex = slotIndex
+		copy(retargeted.frames, t.frames)
+		retargeted.curve = t.curve.clone()
+		return retargeted
+	case *AttachmentTimeline:
+		slotIndex, _ := target.FindSlot(source.slots[t.slotIndex].name)
+		if slotIndex == -1 {
+			return nil
+		}
+		retargeted := NewAttachmentTimeline(t.FrameCount())
+		retargeted.slotIndex = slotIndex
+		for i, time := range t.frames {
+			retargeted.setFrame(i, time, t.attachmentNames[i])
+		}
+		retargeted.resolve(target)
+		return retargeted
+	case *EventTimeline:
+		retargeted := NewEventTimeline(t.FrameCount())
+		for i, event := range t.events {
+			_, eventData := target.FindEvent(event.data.name)
+			if eventData == nil {
+				return nil
+			}
+			e := *event
+			e.data = eventData
+			retargeted.setFrame(i, &e)
+		}
+		return retargeted
+	}
+	return nil
+}
+
+// bone returns the index in target of the bone that the source bone maps
+// to, or -1.
+func (r *retargeter) bone(boneIndex int) int {
+	name := r.source.bones[boneIndex].name
+	if mapped, ok := r.boneMap[name]; ok {
+		name = mapped
+	}
+	if name == "" {
+		return -1
+	}
+	i, _ := r.target.FindBone(name)
+	return i
+}
+
+// setupWorldRotation returns the rotation of the bone in the setup pose
+// relative to the skeleton, or 0 for no bone.
+func setupWorldRotation(bone *BoneData) float32 {
+	var rotation float32
+	for ; bone != nil; bone = bone.parent {
+		rotation += bone.rotation
+	}
+	return rotation
+}
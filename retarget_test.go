@@ -0,0 +1,72 @@
+package spine
+
+import (
+	"strings"
+	"testing"
+)
+
+// retargetJSON is the test skeleton twice the size, with the hip named
+// pelvis and the root turned a quarter turn.
+const retargetJSON = `{
+"bones": [
+  {"name": "root", "rotation": 90},
+  {"name": "pelvis", "parent": "root", "x": 100, "length": 40, "rotation": -90},
+  {"name": "torso", "parent": "pelvis", "length": 80, "rotation": 90},
+  {"name": "head", "parent": "torso", "x": 80, "length": 60}
+],
+"events": {"step": {}}
+}`
+
+func TestRetarget(t *testing.T) {
+	source := loadTest(t)
+	target, err := New(strings.NewReader(retargetJSON), 1, AtlasAttachmentLoader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewAnimationBuilder("move", source)
+	b.Translate("root", 0, 30, 0, CurveLinear)
+	b.Translate("hip", 0, 10, 5, CurveLinear)
+	b.Rotate("hip", 0, 15, CurveLinear)
+	b.Rotate("torso", 0, 20, CurveLinear)
+	b.Rotate("head", 0, 25, CurveLinear)
+	b.Event(0, "step")
+	b.Event(0, "shout")
+	animation, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retargeted, unmapped := Retarget(animation, source, target, map[string]string{"hip": "pelvis", "torso": "chest", "head": ""})
+	if len(unmapped) != 3 {
+		t.Errorf("got %d unmapped timelines, want torso, head and the events", len(unmapped))
+	}
+	for _, timeline := range unmapped {
+		switch timeline := timeline.(type) {
+		case *RotateTimeline:
+			if i := timeline.boneIndex; i != 2 && i != 3 {
+				t.Errorf("bone %d reported unmapped", i)
+			}
+		case *EventTimeline:
+		default:
+			t.Errorf("%T reported unmapped", timeline)
+		}
+	}
+
+	skeleton := NewSkeleton(target)
+	retargeted.Apply(skeleton, 0, false)
+	root, pelvis := skeleton.Bones[0], skeleton.Bones[1]
+	// The root has no length, so it moves by the overall scale, taken from
+	// the only mapped bones with a length: the pelvis, twice the hip.
+	if !near(root.X, 60) || !near(root.Y, 0) {
+		t.Errorf("got root at %v, %v, want 60, 0", root.X, root.Y)
+	}
+	// The pelvis is twice the hip's length, and its parent is turned a
+	// quarter turn counterclockwise, so (10, 5) becomes (20, 10) turned a
+	// quarter turn clockwise.
+	if !near(pelvis.X, 100+10) || !near(pelvis.Y, -20) {
+		t.Errorf("got pelvis at %v, %v, want 110, -20", pelvis.X, pelvis.Y)
+	}
+	if !near(pelvis.Rotation, -90+15) {
+		t.Errorf("got pelvis rotation %v, want %v", pelvis.Rotation, -90+15)
+	}
+}
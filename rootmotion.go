@@ -0,0 +1,125 @@
+package spine
+
+import (
+	"math"
+)
+
+// RootMotion measures how an animation moves the root bone, so that the
+// motion can be given to the entity that owns the skeleton instead of
+// sliding the skeleton around it. Offsets are in the skeleton's coordinate
+// system, before any flip.
+type RootMotion struct {
+	animation *Animation
+	translate *TranslateTimeline
+	rotate    *RotateTimeline
+}
+
+// NewRootMotion returns the root motion of the animation, taken from its
+// translate and rotate timelines for the root bone. An animation that does
+// not key the root bone has no motion.
+func NewRootMotion(animation *Animation) *RootMotion {
+	r := &RootMotion{animation: animation}
+	for _, timeline := range animation.timelines {
+		switch t := timeline.(type) {
+		case *TranslateTimeline:
+			if t.boneIndex == 0 && r.translate == nil {
+				r.translate = t
+			}
+		case *RotateTimeline:
+			if t.boneIndex == 0 && r.rotate == nil {
+				r.rotate = t
+			}
+		}
+	}
+	return r
+}
+
+func (r *RootMotion) Animation() *Animation {
+	return r.animation
+}
+
+// Delta returns how far the root bone moves and turns between two times of
+// the animation. When loop is true, every time the animation wraps between
+// from and to adds the motion of one whole loop, so that a walk cycle keeps
+// moving forward instead of jumping back at the end of each loop.
+func (r *RootMotion) Delta(from, to float32, loop bool) (x, y, rotation float32) {
+	duration := r.animation.duration
+	if !loop || duration == 0 {
+		x1, y1, r1 := r.offset(from)
+		x2, y2, r2 := r.offset(to)
+		return x2 - x1, y2 - y1, r2 - r1
+	}
+
+	loops := float32(math.Floor(float64(to/duration)) - math.Floor(float64(from/duration)))
+	x1, y1, r1 := r.offset(wrapTime(from, duration))
+	x2, y2, r2 := r.offset(wrapTime(to, duration))
+	x, y, rotation = x2-x1, y2-y1, r2-r1
+	if loops != 0 {
+		startX, startY, startRotation := r.offset(0)
+		endX, endY, endRotation := r.offset(duration)
+		x += (endX - startX) * loops
+		y += (endY - startY) * loops
+		rotation += (endRotation - startRotation) * loops
+	}
+	return
+}
+
+// Cancel removes the root motion at time from the root bone, leaving it
+// where it is at the start of the animation. It is meant to be called after
+// the animation has been applied at the same time.
+func (r *RootMotion) Cancel(skeleton *Skeleton, time float32, loop bool) {
+	bone := skeleton.RootBone()
+	if bone == nil || !bone.active {
+		return
+	}
+	if loop && r.animation.duration != 0 {
+		time = wrapTime(time, r.animation.duration)
+	}
+	x, y, rotation := r.Delta(0, time, false)
+	bone.X -= x
+	bone.Y -= y
+	bone.Rotation -= rotation
+}
+
+// offset returns the root bone's offset from the setup pose at time. The
+// rotation counts whole turns, so it changes continuously over the
+// animation.
+func (r *RootMotion) offset(time float32) (x, y, rotation float32) {
+	if r.translate != nil {
+		x, y = r.translate.valueAt(time)
+	}
+	if r.rotate != nil {
+		rotation = r.rotate.totalAngle(time)
+	}
+	return
+}
+
+// totalAngle is like valueAt, but adds up the shortest turn between each
+// pair of frames rather than starting from the last frame's angle.
+func (t *RotateTimeline) totalAngle(time float32) float32 {
+	frames := t.frames
+	angle := frames[1]
+	for i := 0; i+2 < len(frames); i += 2 {
+		if time <= frames[i] {
+			break
+		}
+		amount := wrapAngle(frames[i+3] - frames[i+1])
+		if time >= frames[i+2] {
+			angle += amount
+			continue
+		}
+		percent := (time - frames[i]) / (frames[i+2] - frames[i])
+		angle += amount * t.curve.CurvePercent(i/2, percent)
+		break
+	}
+	return angle
+}
+
+// wrapTime returns time within [0, duration).
+func wrapTime(time, duration float32) float32 {
+	time = float32(math.Mod(float64(time), float64(duration)))
+	if time < 0 {
+		time += duration
+	}
+	return time
+}
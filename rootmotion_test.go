@@ -0,0 +1,67 @@
+package spine
+
+import "testing"
+
+// spinAnimation moves the root 100 along x and turns it a whole turn
+// counterclockwise over one second.
+func spinAnimation(t *testing.T, data *SkeletonData) *Animation {
+	t.Helper()
+	b := NewAnimationBuilder("spin", data)
+	b.Translate("root", 0, 0, 0, CurveLinear)
+	b.Translate("root", 1, 100, 0, CurveLinear)
+	for i := 0; i <= 3; i++ {
+		b.Rotate("root", float32(i)/3, float32(i)*120, CurveLinear)
+	}
+	animation, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return animation
+}
+
+func TestRootMotionDelta(t *testing.T) {
+	motion := NewRootMotion(spinAnimation(t, loadTest(t)))
+	tests := []struct {
+		from, to    float32
+		loop        bool
+		x, rotation float32
+	}{
+		{0.25, 0.75, false, 50, 180},
+		{0, 1, false, 100, 360},
+		{0.75, 0.25, false, -50, -180},
+		{0.5, 1.5, false, 50, 180},
+		{0.75, 1.25, true, 50, 180},
+		{0.5, 2.5, true, 200, 720},
+		{1.25, 0.75, true, -50, -180},
+		{-0.25, 0.25, true, 50, 180},
+	}
+	for _, test := range tests {
+		x, y, rotation := motion.Delta(test.from, test.to, test.loop)
+		if !near(x, test.x) || !near(y, 0) || !near(rotation, test.rotation) {
+			t.Errorf("%v to %v, loop %v: got %v, %v, %v, want %v, 0, %v", test.from, test.to, test.loop, x, y, rotation, test.x, test.rotation)
+		}
+	}
+
+	_, idle := loadTest(t).FindAnimation("idle")
+	if x, y, rotation := NewRootMotion(idle).Delta(0, 2, true); x != 0 || y != 0 || rotation != 0 {
+		t.Errorf("got motion %v, %v, %v for an animation not keying the root", x, y, rotation)
+	}
+}
+
+func TestRootMotionCancel(t *testing.T) {
+	data := loadTest(t)
+	spin := spinAnimation(t, data)
+	motion := NewRootMotion(spin)
+	skeleton := NewSkeleton(data)
+	for _, test := range []struct {
+		time float32
+		loop bool
+	}{{0.5, false}, {1, false}, {1.5, true}, {2.25, true}} {
+		skeleton.SetToSetupPose()
+		spin.Apply(skeleton, test.time, test.loop)
+		motion.Cancel(skeleton, test.time, test.loop)
+		if root := skeleton.RootBone(); !near(root.X, 0) || !near(root.Y, 0) || !near(wrapAngle(root.Rotation), 0) {
+			t.Errorf("at %v: got root at %v, %v turned %v, want it back at the start", test.time, root.X, root.Y, root.Rotation)
+		}
+	}
+}
@@ -1,11 +1,48 @@
 package spine
 
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrBoneNotFound       = errors.New("spine: bone not found")
+	ErrSlotNotFound       = errors.New("spine: slot not found")
+	ErrSkinNotFound       = errors.New("spine: skin not found")
+	ErrAttachmentNotFound = errors.New("spine: attachment not found")
+	ErrAnimationNotFound  = errors.New("spine: animation not found")
+)
+
+// Metadata is what the skeleton section of an exported file says about the
+// skeleton and the editor project it came from.
+type Metadata struct {
+	Hash string
+	// Version is the editor version that exported the file, empty for files
+	// predating it.
+	Version       string
+	X, Y          float32
+	Width, Height float32
+	Fps           float32
+	ImagesPath    string
+	AudioPath     string
+}
+
 type SkeletonData struct {
+	metadata    Metadata
 	bones       []*BoneData
 	slots       []*SlotData
 	skins       []*Skin
 	animations  []*Animation
+	events      []*EventData
 	defaultSkin *Skin
+	warnings    []*LoadError
+
+	boneIndex      map[string]int
+	slotIndex      map[string]int
+	skinIndex      map[string]int
+	animationIndex map[string]int
+	eventIndex     map[string]int
 }
 
 func NewSkeletonData() *SkeletonData {
@@ -14,41 +51,121 @@ func NewSkeletonData() *SkeletonData {
 	data.slots = make([]*SlotData, 0)
 	data.skins = make([]*Skin, 0)
 	data.animations = make([]*Animation, 0)
+	data.boneIndex = make(map[string]int)
+	data.slotIndex = make(map[string]int)
+	data.skinIndex = make(map[string]int)
+	data.animationIndex = make(map[string]int)
+	data.eventIndex = make(map[string]int)
+	data.metadata.Fps = 30
 	return data
 }
 
-func (s *SkeletonData) findBone(name string) (int, *BoneData) {
-	for i, bone := range s.bones {
-		if bone.name == name {
-			return i, bone
-		}
+func (s *SkeletonData) Metadata() Metadata {
+	return s.metadata
+}
+
+func (s *SkeletonData) addBone(bone *BoneData) {
+	if _, ok := s.boneIndex[bone.name]; !ok {
+		s.boneIndex[bone.name] = len(s.bones)
+	}
+	s.bones = append(s.bones, bone)
+}
+
+func (s *SkeletonData) addSlot(slot *SlotData) {
+	if _, ok := s.slotIndex[slot.name]; !ok {
+		s.slotIndex[slot.name] = len(s.slots)
+	}
+	s.slots = append(s.slots, slot)
+}
+
+func (s *SkeletonData) addSkin(skin *Skin) {
+	if _, ok := s.skinIndex[skin.name]; !ok {
+		s.skinIndex[skin.name] = len(s.skins)
+	}
+	s.skins = append(s.skins, skin)
+	if skin.name == "default" && s.defaultSkin == nil {
+		s.defaultSkin = skin
+	}
+}
+
+func (s *SkeletonData) addAnimation(animation *Animation) {
+	if _, ok := s.animationIndex[animation.name]; !ok {
+		s.animationIndex[animation.name] = len(s.animations)
+	}
+	s.animations = append(s.animations, animation)
+}
+
+func (s *SkeletonData) addEvent(event *EventData) {
+	if _, ok := s.eventIndex[event.name]; !ok {
+		s.eventIndex[event.name] = len(s.events)
+	}
+	s.events = append(s.events, event)
+}
+
+// Warnings returns the problems New skipped or ignored while loading.
+func (s *SkeletonData) Warnings() []*LoadError {
+	return s.warnings
+}
+
+func (s *SkeletonData) Bones() []*BoneData {
+	return append([]*BoneData(nil), s.bones...)
+}
+
+func (s *SkeletonData) Slots() []*SlotData {
+	return append([]*SlotData(nil), s.slots...)
+}
+
+// Skins returns the skins in the order they appear in the file.
+func (s *SkeletonData) Skins() []*Skin {
+	return append([]*Skin(nil), s.skins...)
+}
+
+// Animations returns the animations in the order they appear in the file.
+func (s *SkeletonData) Animations() []*Animation {
+	return append([]*Animation(nil), s.animations...)
+}
+
+// Events returns the events in the order they appear in the file.
+func (s *SkeletonData) Events() []*EventData {
+	return append([]*EventData(nil), s.events...)
+}
+
+// DefaultSkin returns the skin named "default", or nil.
+func (s *SkeletonData) DefaultSkin() *Skin {
+	return s.defaultSkin
+}
+
+func (s *SkeletonData) FindBone(name string) (int, *BoneData) {
+	if i, ok := s.boneIndex[name]; ok {
+		return i, s.bones[i]
 	}
 	return -1, nil
 }
 
-func (s *SkeletonData) findSlot(name string) (int, *SlotData) {
-	for i, slot := range s.slots {
-		if slot.name == name {
-			return i, slot
-		}
+func (s *SkeletonData) FindSlot(name string) (int, *SlotData) {
+	if i, ok := s.slotIndex[name]; ok {
+		return i, s.slots[i]
 	}
 	return -1, nil
 }
 
-func (s *SkeletonData) findSkin(name string) (int, *Skin) {
-	for i, skin := range s.skins {
-		if skin.name == name {
-			return i, skin
-		}
+func (s *SkeletonData) FindSkin(name string) (int, *Skin) {
+	if i, ok := s.skinIndex[name]; ok {
+		return i, s.skins[i]
 	}
 	return -1, nil
 }
 
-func (s *SkeletonData) findAnimation(name string) (int, *Animation) {
-	for i, animation := range s.animations {
-		if animation.name == name {
-			return i, animation
-		}
+func (s *SkeletonData) FindAnimation(name string) (int, *Animation) {
+	if i, ok := s.animationIndex[name]; ok {
+		return i, s.animations[i]
+	}
+	return -1, nil
+}
+
+func (s *SkeletonData) FindEvent(name string) (int, *EventData) {
+	if i, ok := s.eventIndex[name]; ok {
+		return i, s.events[i]
 	}
 	return -1, nil
 }
@@ -79,7 +196,7 @@ func NewSkeleton(skeletonData *SkeletonData) *Skeleton {
 	for _, boneData := range skeletonData.bones {
 		var parent *Bone
 		if boneData.parent != nil {
-			i, _ := skeletonData.findBone(boneData.parent.name)
+			i, _ := skeletonData.FindBone(boneData.parent.name)
 			parent = skeleton.Bones[i]
 		}
 		skeleton.Bones = append(skeleton.Bones, NewBone(boneData, parent))
@@ -88,19 +205,87 @@ func NewSkeleton(skeletonData *SkeletonData) *Skeleton {
 	skeleton.Slots = make([]*Slot, 0)
 	skeleton.DrawOrder = make([]*Slot, 0)
 	for _, slotData := range skeletonData.slots {
-		i, _ := skeletonData.findBone(slotData.boneData.name)
+		i, _ := skeletonData.FindBone(slotData.boneData.name)
 		bone := skeleton.Bones[i]
 		slot := NewSlot(slotData, skeleton, bone)
 		skeleton.Slots = append(skeleton.Slots, slot)
 		skeleton.DrawOrder = append(skeleton.DrawOrder, slot)
 	}
+	skeleton.updateActive()
 
 	return skeleton
 }
 
+// Clone returns a copy of the skeleton in its current pose, with its own
+// bones, slots and draw order. The skeleton data, skin and attachments are
+// shared with the original, so changing the contents of the skin changes it
+// for both; to change one skeleton's skin, set a copy of it with SetSkin.
+func (s *Skeleton) Clone() *Skeleton {
+	clone := new(Skeleton)
+	*clone = *s
+
+	bones := make(map[*Bone]*Bone, len(s.Bones))
+	clone.Bones = make([]*Bone, len(s.Bones))
+	for i, bone := range s.Bones {
+		b := *bone
+		if bone.parent != nil {
+			b.parent = bones[bone.parent]
+		}
+		bones[bone] = &b
+		clone.Bones[i] = &b
+	}
+
+	slots := make(map[*Slot]*Slot, len(s.Slots))
+	clone.Slots = make([]*Slot, len(s.Slots))
+	for i, slot := range s.Slots {
+		c := *slot
+		c.skeleton = clone
+		c.Bone = bones[slot.Bone]
+		slots[slot] = &c
+		clone.Slots[i] = &c
+	}
+
+	clone.DrawOrder = make([]*Slot, len(s.DrawOrder))
+	for i, slot := range s.DrawOrder {
+		clone.DrawOrder[i] = slots[slot]
+	}
+	return clone
+}
+
+// updateActive deactivates the bones that require a skin the current skin
+// does not provide. A bone in the skin also activates its ancestors. Bones
+// of a skin composed at runtime that this skeleton lacks are ignored.
+func (s *Skeleton) updateActive() {
+	for _, bone := range s.Bones {
+		bone.active = !bone.Data.skinRequired
+	}
+	if s.skin == nil {
+		return
+	}
+	for _, boneData := range s.skin.bones {
+		i, _ := s.data.FindBone(boneData.name)
+		if i == -1 {
+			continue
+		}
+		for bone := s.Bones[i]; bone != nil; bone = bone.parent {
+			bone.active = true
+		}
+	}
+}
+
+func (s *Skeleton) Data() *SkeletonData {
+	return s.data
+}
+
+func (s *Skeleton) Skin() *Skin {
+	return s.skin
+}
+
 func (s *Skeleton) UpdateWorldTransform() {
 	for _, bone := range s.Bones {
-		bone.UpdateWorldTransform(s.FlipX, s.FlipY)
+		if bone.active {
+			bone.UpdateWorldTransform(s.FlipX, s.FlipY)
+		}
 	}
 }
 
@@ -129,41 +314,82 @@ func (s *Skeleton) RootBone() *Bone {
 }
 
 func (s *Skeleton) FindBone(name string) (int, *Bone) {
-	for i, bone := range s.Bones {
-		if bone.name == name {
-			return i, bone
-		}
+	i, _ := s.data.FindBone(name)
+	if i == -1 {
+		return -1, nil
 	}
-	return -1, nil
+	return i, s.Bones[i]
 }
 
 func (s *Skeleton) FindSlot(name string) (int, *Slot) {
-	for i, slot := range s.Slots {
-		if slot.data.name == name {
-			return i, slot
-		}
+	i, _ := s.data.FindSlot(name)
+	if i == -1 {
+		return -1, nil
 	}
-	return -1, nil
+	return i, s.Slots[i]
 }
 
 func (s *Skeleton) SetSkinByName(name string) {
-	_, skin := s.data.findSkin(name)
+	if err := s.TrySetSkinByName(name); err != nil {
+		panic(err)
+	}
+}
+
+// TrySetSkinByName is like SetSkinByName but returns an error wrapping
+// ErrSkinNotFound instead of panicking.
+func (s *Skeleton) TrySetSkinByName(name string) error {
+	_, skin := s.data.FindSkin(name)
 	if skin == nil {
-		panic("Skin not found: " + name)
+		return fmt.Errorf("%w: %s", ErrSkinNotFound, name)
 	}
 	s.SetSkin(skin)
+	return nil
 }
 
+// SetSkin sets the skin used to look up attachments, which may be one from
+// the skeleton data or one composed at runtime. Attachments from the previous
+// skin that are showing are replaced by the new skin's. Without a previous
+// skin, slots show the new skin's setup pose attachments where it has them.
 func (s *Skeleton) SetSkin(skin *Skin) {
-	if s.skin != nil && skin != nil {
-		skin.attachAll(s, s.skin)
+	if skin != nil {
+		if s.skin != nil {
+			skin.attachAll(s, s.skin)
+		} else {
+			for i, slot := range s.Slots {
+				name := slot.data.attachmentName
+				if name == "" {
+					continue
+				}
+				if attachment := skin.Attachment(i, name); attachment != nil {
+					slot.SetAttachment(attachment)
+					slot.nameAttachment(attachment, name)
+				}
+			}
+		}
 	}
 	s.skin = skin
+	s.updateActive()
 }
 
+// AttachmentBySlotName returns the named attachment for the slot, or nil if
+// either cannot be found.
 func (s *Skeleton) AttachmentBySlotName(slot string, attachment string) Attachment {
-	i, _ := s.data.findSlot(slot)
-	return s.AttachmentBySlotIndex(i, attachment)
+	a, _ := s.TryAttachmentBySlotName(slot, attachment)
+	return a
+}
+
+// TryAttachmentBySlotName is like AttachmentBySlotName but returns an error
+// wrapping ErrSlotNotFound or ErrAttachmentNotFound when the lookup fails.
+func (s *Skeleton) TryAttachmentBySlotName(slot string, attachment string) (Attachment, error) {
+	i, _ := s.data.FindSlot(slot)
+	if i == -1 {
+		return nil, fmt.Errorf("%w: %s", ErrSlotNotFound, slot)
+	}
+	a := s.AttachmentBySlotIndex(i, attachment)
+	if a == nil {
+		return nil, fmt.Errorf("%w: %s, for slot: %s", ErrAttachmentNotFound, attachment, slot)
+	}
+	return a, nil
 }
 
 func (s *Skeleton) AttachmentBySlotIndex(index int, name string) Attachment {
@@ -179,28 +405,104 @@ func (s *Skeleton) AttachmentBySlotIndex(index int, name string) Attachment {
 	return nil
 }
 
+// attachmentName returns the name attachment is stored under for the slot in
+// the skin or the default skin, falling back to the attachment's own name.
+func (s *Skeleton) attachmentName(slotIndex int, attachment Attachment) string {
+	if attachment == nil {
+		return ""
+	}
+	if slot := s.Slots[slotIndex]; slot.namedAttachment == attachment {
+		return slot.attachmentName
+	}
+	for _, skin := range [...]*Skin{s.skin, s.data.defaultSkin} {
+		if skin == nil {
+			continue
+		}
+		if name := skin.attachmentName(slotIndex, attachment); name != "" {
+			return name
+		}
+	}
+	return attachment.Name()
+}
+
 func (s *Skeleton) SetAttachment(slotName, attachmentName string) {
-	for i, slot := range s.Slots {
-		if slot.data.name == slotName {
-			var attachment Attachment
-			if attachmentName != "" {
-				attachment = s.AttachmentBySlotIndex(i, attachmentName)
-				if attachment == nil {
-					panic("Attachment not found: " + attachmentName + ", for slot: " + slotName)
-				}
-			}
-			slot.SetAttachment(attachment)
-			return
+	if err := s.TrySetAttachment(slotName, attachmentName); err != nil {
+		panic(err)
+	}
+}
+
+// TrySetAttachment is like SetAttachment but returns an error wrapping
+// ErrSlotNotFound or ErrAttachmentNotFound instead of panicking. An empty
+// attachment name clears the slot.
+func (s *Skeleton) TrySetAttachment(slotName, attachmentName string) error {
+	i, slot := s.FindSlot(slotName)
+	if slot == nil {
+		return fmt.Errorf("%w: %s", ErrSlotNotFound, slotName)
+	}
+	var attachment Attachment
+	if attachmentName != "" {
+		attachment = s.AttachmentBySlotIndex(i, attachmentName)
+		if attachment == nil {
+			return fmt.Errorf("%w: %s, for slot: %s", ErrAttachmentNotFound, attachmentName, slotName)
 		}
 	}
-	panic("Slot not found: " + slotName)
+	slot.SetAttachment(attachment)
+	slot.nameAttachment(attachment, attachmentName)
+	return nil
 }
 
 func (s *Skeleton) FindAnimation(name string) *Animation {
-	_, a := s.data.findAnimation(name)
+	_, a := s.data.FindAnimation(name)
 	return a
 }
 
+// TryFindAnimation is like FindAnimation but returns an error wrapping
+// ErrAnimationNotFound instead of nil.
+func (s *Skeleton) TryFindAnimation(name string) (*Animation, error) {
+	_, a := s.data.FindAnimation(name)
+	if a == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAnimationNotFound, name)
+	}
+	return a, nil
+}
+
 func (s *Skeleton) Update(dt float32) {
 	s.time += dt
 }
+
+// Bounds returns the axis-aligned rectangle enclosing the world vertices of
+// every attachment in the draw order. The world transform must be up to date.
+// All values are zero when no slot has a visible attachment.
+func (s *Skeleton) Bounds() (minX, minY, maxX, maxY float32) {
+	minX, minY, maxX, maxY, _ = s.bounds()
+	return
+}
+
+func (s *Skeleton) bounds() (minX, minY, maxX, maxY float32, ok bool) {
+	for _, slot := range s.DrawOrder {
+		if !slot.Bone.active {
+			continue
+		}
+		var verts []float32
+		switch attachment := slot.Attachment.(type) {
+		case *RegionAttachment:
+			v := attachment.Update(slot)
+			verts = v[:]
+		default:
+			continue
+		}
+		for i := 0; i+1 < len(verts); i += 2 {
+			x, y := verts[i], verts[i+1]
+			if !ok {
+				minX, minY, maxX, maxY = x, y, x, y
+				ok = true
+				continue
+			}
+			minX = float32(math.Min(float64(minX), float64(x)))
+			minY = float32(math.Min(float64(minY), float64(y)))
+			maxX = float32(math.Max(float64(maxX), float64(x)))
+			maxY = float32(math.Max(float64(maxY), float64(y)))
+		}
+	}
+	return
+}
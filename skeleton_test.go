@@ -0,0 +1,194 @@
+package spine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func BenchmarkAttachmentBySlotIndex(b *testing.B) {
+	skeleton := NewSkeleton(loadTest(b))
+	skeleton.SetSkinByName("fancy")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		skeleton.AttachmentBySlotIndex(1, "head")
+		skeleton.AttachmentBySlotIndex(2, "hat")
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skeleton.SetSkinByName("fancy")
+	skeleton.SetAttachment("hat", "hat")
+	walk := skeleton.FindAnimation("walk")
+	walk.Apply(skeleton, 0.25, false)
+
+	clone := skeleton.Clone()
+	if clone.Bones[1].Rotation != skeleton.Bones[1].Rotation {
+		t.Fatal("clone does not start in the same pose")
+	}
+	walk.Apply(clone, 0.5, false)
+	clone.Slots[1].R = 0.5
+	if clone.Skin() != skeleton.Skin() {
+		t.Error("clone does not share the skin")
+	}
+	plain := clone.Skin().Copy("plain")
+	plain.RemoveAttachment(2, "hat")
+	clone.SetSkin(plain)
+
+	if skeleton.Bones[1].Rotation == clone.Bones[1].Rotation {
+		t.Error("posing the clone changed the original's bones")
+	}
+	if skeleton.Slots[1].R == 0.5 {
+		t.Error("changing the clone's slot changed the original's")
+	}
+	if skeleton.Skin().Name() != "fancy" || skeleton.Skin().Attachment(2, "hat") == nil {
+		t.Error("setting the clone's skin changed the original's")
+	}
+	if clone.Slots[2].Attachment == nil || clone.Slots[1].Bone != clone.Bones[3] {
+		t.Error("clone slots do not point at the clone's bones and attachments")
+	}
+}
+
+func TestCloneUsesResolvedAttachments(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skeleton.SetSkinByName("fancy")
+	clone := skeleton.Clone()
+	for _, timeline := range skeleton.FindAnimation("walk").Timelines() {
+		if timeline, ok := timeline.(*AttachmentTimeline); ok {
+			if _, ok := timeline.resolved[clone.skin]; !ok {
+				t.Error("the clone's skin misses the attachments resolved at load")
+			}
+		}
+	}
+}
+
+func TestSkeletonBounds(t *testing.T) {
+	data := loadTest(t)
+	skeleton := NewSkeleton(data)
+	skeleton.UpdateWorldTransform()
+	// The torso is 32 long and 64 wide, centered 20 up the upright torso
+	// bone at y 50. The head is 64 square, centered 10 past the head bone
+	// at y 90.
+	want := [4]float32{-32, 54, 32, 132}
+	if got := boundsOf(skeleton.Bounds()); !nearAll(got, want) {
+		t.Errorf("got setup bounds %v, want %v", got, want)
+	}
+
+	b := NewAnimationBuilder("slide", data)
+	b.Translate("root", 0, 0, 0, CurveLinear)
+	b.Translate("root", 1, 100, 0, CurveLinear)
+	slide, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = [4]float32{-32, 54, 132, 132}
+	if got := boundsOf(slide.Bounds(skeleton, 10)); !nearAll(got, want) {
+		t.Errorf("got animation bounds %v, want %v", got, want)
+	}
+
+	// Bones that need a skin that is not set are left out.
+	data, err = loadJSON(t, strings.Replace(testJSON, `"x": 40, "length": 30`, `"x": 40, "length": 30, "skin": true`, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skeleton = NewSkeleton(data)
+	skeleton.UpdateWorldTransform()
+	want = [4]float32{-32, 54, 32, 86}
+	if got := boundsOf(skeleton.Bounds()); !nearAll(got, want) {
+		t.Errorf("got bounds %v without the head bone, want %v", got, want)
+	}
+}
+
+func boundsOf(minX, minY, maxX, maxY float32) [4]float32 {
+	return [4]float32{minX, minY, maxX, maxY}
+}
+
+func nearAll(a, b [4]float32) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFindMatchesLinearSearch(t *testing.T) {
+	data := loadTest(t)
+	// linear returns the index of the first name matching, or -1.
+	linear := func(names []string, name string) int {
+		for i, n := range names {
+			if n == name {
+				return i
+			}
+		}
+		return -1
+	}
+	var bones, slots, skins, animations, events []string
+	for _, bone := range data.Bones() {
+		bones = append(bones, bone.Name())
+	}
+	for _, slot := range data.Slots() {
+		slots = append(slots, slot.Name())
+	}
+	for _, skin := range data.Skins() {
+		skins = append(skins, skin.Name())
+	}
+	for _, animation := range data.Animations() {
+		animations = append(animations, animation.Name())
+	}
+	for _, event := range data.Events() {
+		events = append(events, event.Name())
+	}
+
+	names := []string{"missing", ""}
+	for _, list := range [][]string{bones, slots, skins, animations, events} {
+		names = append(names, list...)
+	}
+	for _, name := range names {
+		i, _ := data.FindBone(name)
+		j, _ := data.FindSlot(name)
+		k, _ := data.FindSkin(name)
+		l, _ := data.FindAnimation(name)
+		m, _ := data.FindEvent(name)
+		got := [5]int{i, j, k, l, m}
+		want := [5]int{linear(bones, name), linear(slots, name), linear(skins, name), linear(animations, name), linear(events, name)}
+		if got != want {
+			t.Errorf("%q: got indexes %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTryErrors(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	_, err1 := skeleton.TryAttachmentBySlotName("tail", "head")
+	_, err2 := skeleton.TryAttachmentBySlotName("head", "hat")
+	_, err3 := skeleton.TryFindAnimation("run")
+	b := NewAnimationBuilder("", skeleton.Data())
+	b.Rotate("tail", 0, 0, CurveLinear)
+	_, err4 := b.Build()
+	tests := []struct {
+		err, want error
+	}{
+		{skeleton.TrySetSkinByName("plain"), ErrSkinNotFound},
+		{err1, ErrSlotNotFound},
+		{err2, ErrAttachmentNotFound},
+		{skeleton.TrySetAttachment("tail", "head"), ErrSlotNotFound},
+		{skeleton.TrySetAttachment("head", "hat"), ErrAttachmentNotFound},
+		{err3, ErrAnimationNotFound},
+		{err4, ErrBoneNotFound},
+	}
+	for i, test := range tests {
+		if !errors.Is(test.err, test.want) {
+			t.Errorf("%d: got %v, want %v", i, test.err, test.want)
+		}
+	}
+
+	if err := skeleton.TrySetAttachment("head", ""); err != nil || skeleton.Slots[1].Attachment != nil {
+		t.Errorf("clearing the slot: %v", err)
+	}
+	if err := skeleton.TrySetSkinByName("fancy"); err != nil || skeleton.Skin().Name() != "fancy" {
+		t.Errorf("setting the skin: %v", err)
+	}
+}
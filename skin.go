@@ -1,46 +1,159 @@
 package spine
 
 import (
-	"fmt"
+	"sort"
 )
 
-type skinData struct {
-	Index      int
-	Name       string
-	Attachment Attachment
+type skinKey struct {
+	slotIndex int
+	name      string
 }
 
 type Skin struct {
 	name        string
-	attachments map[string]*skinData
+	attachments map[skinKey]Attachment
+	bones       []*BoneData
+	constraints []string
+	revision    int
 }
 
 func NewSkin(name string) *Skin {
 	skin := new(Skin)
 	skin.name = name
-	skin.attachments = make(map[string]*skinData)
+	skin.attachments = make(map[skinKey]Attachment)
 	return skin
 }
 
+func (s *Skin) Name() string {
+	return s.name
+}
+
 func (s *Skin) AddAttachment(slotIndex int, name string, attachment Attachment) {
-	data := &skinData{slotIndex, name, attachment}
-	s.attachments[fmt.Sprintf("%v:%v", slotIndex, name)] = data
+	s.attachments[skinKey{slotIndex, name}] = attachment
+	s.revision++
 }
 
 func (s *Skin) Attachment(slotIndex int, name string) Attachment {
-	values, ok := s.attachments[fmt.Sprintf("%v:%v", slotIndex, name)]
-	if !ok {
-		return nil
+	return s.attachments[skinKey{slotIndex, name}]
+}
+
+// attachmentName returns the name attachment is stored under for the slot,
+// or "" if the skin does not have it.
+func (s *Skin) attachmentName(slotIndex int, attachment Attachment) string {
+	if name := attachment.Name(); s.attachments[skinKey{slotIndex, name}] == attachment {
+		return name
+	}
+	for key, a := range s.attachments {
+		if key.slotIndex == slotIndex && a == attachment {
+			return key.name
+		}
+	}
+	return ""
+}
+
+func (s *Skin) RemoveAttachment(slotIndex int, name string) {
+	key := skinKey{slotIndex, name}
+	if _, ok := s.attachments[key]; ok {
+		delete(s.attachments, key)
+		s.revision++
+	}
+}
+
+// AddBone adds a bone that is only active while this skin is. Bones that are
+// already in the skin are ignored.
+func (s *Skin) AddBone(bone *BoneData) {
+	for _, b := range s.bones {
+		if b == bone {
+			return
+		}
+	}
+	s.bones = append(s.bones, bone)
+}
+
+func (s *Skin) Bones() []*BoneData {
+	return append([]*BoneData(nil), s.bones...)
+}
+
+// AddConstraint adds the name of a constraint that is only active while this
+// skin is. Constraints are not applied by this runtime; the names are kept
+// for tools and for skins composed at runtime.
+func (s *Skin) AddConstraint(name string) {
+	for _, c := range s.constraints {
+		if c == name {
+			return
+		}
 	}
-	return values.Attachment
+	s.constraints = append(s.constraints, name)
 }
 
+func (s *Skin) Constraints() []string {
+	return append([]string(nil), s.constraints...)
+}
+
+// SkinEntry is an attachment in a skin along with the slot index and name it
+// is stored under.
+type SkinEntry struct {
+	SlotIndex  int
+	Name       string
+	Attachment Attachment
+}
+
+// Entries returns the attachments in the skin, ordered by slot index and
+// then by name.
+func (s *Skin) Entries() []SkinEntry {
+	entries := make([]SkinEntry, 0, len(s.attachments))
+	for key, attachment := range s.attachments {
+		entries = append(entries, SkinEntry{key.slotIndex, key.name, attachment})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].SlotIndex != entries[j].SlotIndex {
+			return entries[i].SlotIndex < entries[j].SlotIndex
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// AddSkin adds all attachments, bones and constraints from other to the
+// skin, replacing attachments stored under the same slot index and name.
+func (s *Skin) AddSkin(other *Skin) {
+	for key, attachment := range other.attachments {
+		s.attachments[key] = attachment
+	}
+	for _, bone := range other.bones {
+		s.AddBone(bone)
+	}
+	for _, constraint := range other.constraints {
+		s.AddConstraint(constraint)
+	}
+	s.revision++
+}
+
+// Copy returns a new skin with the given name and the same attachments,
+// bones and constraints. The attachments themselves are shared.
+func (s *Skin) Copy(name string) *Skin {
+	skin := NewSkin(name)
+	skin.AddSkin(s)
+	return skin
+}
+
+// attachAll replaces the attachments from oldSkin that are currently shown
+// with the ones this skin, or failing that the default skin, stores under
+// the same name. Slots with no match are left as they are.
 func (s *Skin) attachAll(skeleton *Skeleton, oldSkin *Skin) {
-	for _, val := range oldSkin.attachments {
-		slot := skeleton.Slots[val.Index]
-		attachment := s.Attachment(val.Index, val.Name)
+	defaultSkin := skeleton.data.defaultSkin
+	for key, oldAttachment := range oldSkin.attachments {
+		slot := skeleton.Slots[key.slotIndex]
+		if slot.Attachment != oldAttachment {
+			continue
+		}
+		attachment := s.Attachment(key.slotIndex, key.name)
+		if attachment == nil && defaultSkin != nil {
+			attachment = defaultSkin.Attachment(key.slotIndex, key.name)
+		}
 		if attachment != nil {
 			slot.SetAttachment(attachment)
+			slot.nameAttachment(attachment, key.name)
 		}
 	}
 }
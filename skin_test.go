@@ -0,0 +1,41 @@
+package spine
+
+import "testing"
+
+func TestSetSkinKeepsUnmatchedAttachments(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skeleton.SetSkinByName("fancy")
+	skeleton.SetAttachment("hat", "hat")
+	hat := skeleton.Slots[2].Attachment
+	if hat == nil {
+		t.Fatal("hat not shown")
+	}
+	skeleton.SetSkin(NewSkin("plain"))
+	if skeleton.Slots[2].Attachment != hat {
+		t.Fatal("switching to a skin without a hat cleared the hat slot")
+	}
+}
+
+func TestSetSkinIgnoresForeignBones(t *testing.T) {
+	skeleton := NewSkeleton(loadTest(t))
+	skin := NewSkin("composed")
+	skin.AddBone(NewBoneData("tail", nil))
+	head := skeleton.Slots[1].Attachment
+	before := CapturePose(skeleton)
+	skeleton.SetSkin(skin)
+
+	if skeleton.Slots[1].Attachment != head {
+		t.Error("switching skins changed the head attachment")
+	}
+	for i, bone := range skeleton.Bones {
+		if !bone.active {
+			t.Errorf("bone %s is inactive", bone.name)
+		}
+		if bp := before.Bones[i]; bp != (BonePose{bone.X, bone.Y, bone.Rotation, bone.ScaleX, bone.ScaleY}) {
+			t.Errorf("bone %s moved", bone.name)
+		}
+	}
+	if len(skeleton.Bones) != 4 {
+		t.Errorf("got %d bones, want the skin's bone left out", len(skeleton.Bones))
+	}
+}
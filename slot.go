@@ -18,6 +18,24 @@ func NewSlotData(name string, boneData *BoneData) *SlotData {
 	return slotData
 }
 
+func (s *SlotData) Name() string {
+	return s.name
+}
+
+func (s *SlotData) BoneData() *BoneData {
+	return s.boneData
+}
+
+// Color returns the setup pose color.
+func (s *SlotData) Color() (r, g, b, a float32) {
+	return s.r, s.g, s.b, s.a
+}
+
+// AttachmentName returns the name of the setup pose attachment, or "".
+func (s *SlotData) AttachmentName() string {
+	return s.attachmentName
+}
+
 type Slot struct {
 	data           *SlotData
 	skeleton       *Skeleton
@@ -25,6 +43,12 @@ type Slot struct {
 	R, G, B, A     float32
 	attachmentTime float32
 	Attachment     Attachment
+
+	// attachmentName is the name the skins store namedAttachment under, kept
+	// when an attachment is shown by name so that Capture need not search
+	// the skins for it.
+	attachmentName  string
+	namedAttachment Attachment
 }
 
 func NewSlot(slotData *SlotData, skeleton *Skeleton, bone *Bone) *Slot {
@@ -40,6 +64,10 @@ func NewSlot(slotData *SlotData, skeleton *Skeleton, bone *Bone) *Slot {
 	return slot
 }
 
+func (s *Slot) Data() *SlotData {
+	return s.data
+}
+
 func (s *Slot) SetToSetupPose() {
 	data := s.data
 	s.R = data.r
@@ -47,9 +75,15 @@ func (s *Slot) SetToSetupPose() {
 	s.B = data.b
 	s.A = data.a
 
+	if i, slotData := s.skeleton.data.FindSlot(data.name); slotData == data {
+		s.SetAttachment(s.skeleton.AttachmentBySlotIndex(i, data.attachmentName))
+		s.nameAttachment(s.Attachment, data.attachmentName)
+		return
+	}
 	for i, slotData := range s.skeleton.data.slots {
 		if slotData == data {
 			s.SetAttachment(s.skeleton.AttachmentBySlotIndex(i, data.attachmentName))
+			s.nameAttachment(s.Attachment, data.attachmentName)
 			return
 		}
 	}
@@ -60,6 +94,11 @@ func (s *Slot) SetAttachment(attachment Attachment) {
 	s.attachmentTime = s.skeleton.time
 }
 
+// nameAttachment records that attachment is stored under name for this slot.
+func (s *Slot) nameAttachment(attachment Attachment, name string) {
+	s.attachmentName, s.namedAttachment = name, attachment
+}
+
 func (s *Slot) SetAttachmentTime(time float32) {
 	s.attachmentTime = s.skeleton.time - time
 }
@@ -8,50 +8,6 @@ import (
 	"strconv"
 )
 
-type fileAnim struct {
-	Bones map[string]map[string][]map[string]interface{} `json:"bones"`
-	Slots map[string]map[string][]map[string]interface{} `json:"slots"`
-}
-
-type fileSlot struct {
-	Bone       string `json:"bone"`
-	Name       string `json:"name"`
-	Color      string `json:"color"`
-	Attachment string `json:"attachment"`
-}
-
-type fileBone struct {
-	Name   string `json:"name"`
-	Parent string `json:"parent"`
-
-	Length   interface{} `json:"length"`
-	Rotation interface{} `json:"rotation"`
-	X        interface{} `json:"x"`
-	Y        interface{} `json:"y"`
-	ScaleX   interface{} `json:"scaleX"`
-	ScaleY   interface{} `json:"scaleY"`
-}
-
-type fileAttachment struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
-
-	Rotation interface{} `json:"rotation"`
-	X        interface{} `json:"x"`
-	Y        interface{} `json:"y"`
-	ScaleX   interface{} `json:"scaleX"`
-	ScaleY   interface{} `json:"scaleY"`
-	Width    interface{} `json:"width"`
-	Height   interface{} `json:"height"`
-}
-
-type fileRoot struct {
-	Bones      []fileBone                                      `json:"bones"`
-	Slots      []fileSlot                                      `json:"slots"`
-	Skins      map[string]map[string]map[string]fileAttachment `json:"skins"`
-	Animations map[string]fileAnim                             `json:"animations"`
-}
-
 type AttachmentLoader interface {
 	NewAttachment(skin *Skin, _type, name string) (Attachment, error)
 }
@@ -80,274 +36,886 @@ func (a AtlasAttachmentLoader) NewAttachment(skin *Skin, _type, name string) (At
 	return attachment, nil
 }
 
-func New(r io.Reader, scale float32, loader AttachmentLoader) (*SkeletonData, error) {
-	var root fileRoot
-	err := json.NewDecoder(r).Decode(&root)
+// LoadMode selects how New treats problems in the skeleton JSON.
+type LoadMode int
+
+const (
+	// Lenient skips bones, slots, attachments, timelines and animations that
+	// cannot be loaded and records a warning for each. Sections and
+	// timelines of features this runtime does not support are skipped the
+	// same way.
+	Lenient LoadMode = iota
+	// Strict fails on the first problem, including unknown or unsupported
+	// timeline and attachment types and references to missing bones or
+	// slots.
+	Strict
+)
+
+type LoadOption func(*skeletonReader)
+
+// WithLoadMode sets the load mode. The default is Lenient.
+func WithLoadMode(mode LoadMode) LoadOption {
+	return func(r *skeletonReader) {
+		r.mode = mode
+	}
+}
+
+func New(r io.Reader, scale float32, loader AttachmentLoader, options ...LoadOption) (*SkeletonData, error) {
+	if scale <= 0 {
+		return nil, errors.New("spine: scale must be positive")
+	}
+	value, err := decodeJSON(json.NewDecoder(r))
+	if err != nil {
+		return nil, &LoadError{Err: errors.New("failed to parse skeleton json: " + err.Error())}
+	}
+	root, err := newJSONObject("", value)
 	if err != nil {
-		return nil, errors.New("failed to parse skeleton json: " + err.Error())
+		return nil, err
 	}
 
-	skeletonData := NewSkeletonData()
+	skeletonMap, err := root.object("skeleton")
+	if err != nil {
+		return nil, err
+	}
+	version := ""
+	if skeletonMap != nil {
+		if version, err = skeletonMap.string("spine", ""); err != nil {
+			return nil, err
+		}
+	}
+	f, err := newFormat(version)
+	if err != nil {
+		return nil, &LoadError{Path: "skeleton.spine", Err: err}
+	}
 
-	// Bones
-	for _, bone := range root.Bones {
-		boneName := bone.Name
-		var boneParent *BoneData
-		if parentName := bone.Parent; parentName != "" {
-			_, boneParent = skeletonData.findBone(parentName)
-			if boneParent == nil {
-				return nil, errors.New("Parent bone not found: " + parentName)
+	reader := &skeletonReader{
+		format: f,
+		scale:  scale,
+		loader: loader,
+		data:   NewSkeletonData(),
+	}
+	for _, option := range options {
+		option(reader)
+	}
+	reader.data.metadata.Version = version
+	if skeletonMap != nil {
+		if err := reader.readSkeleton(skeletonMap); err != nil {
+			return nil, err
+		}
+	}
+	if err := reader.readBones(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readSlots(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readSkins(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readEvents(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readAnimations(root); err != nil {
+		return nil, err
+	}
+	for _, section := range f.unsupportedSections {
+		if root.has(section) {
+			if err := reader.skip(loadError(section, "unsupported section: %s", section)); err != nil {
+				return nil, err
 			}
 		}
+	}
+	return reader.data, nil
+}
 
-		boneData := NewBoneData(boneName, boneParent)
+type skeletonReader struct {
+	format *format
+	scale  float32
+	loader AttachmentLoader
+	mode   LoadMode
+	data   *SkeletonData
+}
+
+// skip reports err as a warning and returns nil in lenient mode, so that the
+// caller moves on to the next item. In strict mode it returns err.
+func (r *skeletonReader) skip(err error) error {
+	if err == nil || r.mode == Strict {
+		return err
+	}
+	r.warn(err)
+	return nil
+}
+
+func (r *skeletonReader) warn(err error) {
+	loadErr, ok := err.(*LoadError)
+	if !ok {
+		loadErr = &LoadError{Err: err}
+	}
+	r.data.warnings = append(r.data.warnings, loadErr)
+}
 
-		if length, ok := bone.Length.(float64); ok {
-			boneData.Length = float32(length) * scale
+func (r *skeletonReader) readSkeleton(skeletonMap *jsonObject) error {
+	var err error
+	data := &r.data.metadata
+	if data.Hash, err = skeletonMap.string("hash", ""); err != nil {
+		return err
+	}
+	if data.ImagesPath, err = skeletonMap.string("images", ""); err != nil {
+		return err
+	}
+	if data.AudioPath, err = skeletonMap.string("audio", ""); err != nil {
+		return err
+	}
+	if data.X, err = skeletonMap.float("x", 0); err != nil {
+		return err
+	}
+	if data.Y, err = skeletonMap.float("y", 0); err != nil {
+		return err
+	}
+	if data.Width, err = skeletonMap.float("width", 0); err != nil {
+		return err
+	}
+	if data.Height, err = skeletonMap.float("height", 0); err != nil {
+		return err
+	}
+	if data.Fps, err = skeletonMap.float("fps", data.Fps); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *skeletonReader) readBones(root *jsonObject) error {
+	bones, err := root.array("bones")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < bones.len(); i++ {
+		if err := r.skip(r.readBone(bones, i)); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if x, ok := bone.X.(float64); ok {
-			boneData.x = float32(x) * scale
+func (r *skeletonReader) readBone(bones *jsonArray, i int) error {
+	boneMap, err := bones.object(i)
+	if err != nil {
+		return err
+	}
+	name, err := boneMap.requiredString("name")
+	if err != nil {
+		return err
+	}
+	var parent *BoneData
+	parentName, err := boneMap.string("parent", "")
+	if err != nil {
+		return err
+	}
+	if parentName != "" {
+		if _, parent = r.data.FindBone(parentName); parent == nil {
+			return loadError(boneMap.pathTo("parent"), "parent bone not found: %s", parentName)
 		}
+	}
+
+	boneData := NewBoneData(name, parent)
+	if boneData.Length, err = boneMap.float("length", 0); err != nil {
+		return err
+	}
+	boneData.Length *= r.scale
+	if boneData.x, err = boneMap.float("x", 0); err != nil {
+		return err
+	}
+	boneData.x *= r.scale
+	if boneData.y, err = boneMap.float("y", 0); err != nil {
+		return err
+	}
+	boneData.y *= r.scale
+	if boneData.rotation, err = boneMap.float("rotation", 0); err != nil {
+		return err
+	}
+	if boneData.scaleX, err = boneMap.float("scaleX", 1); err != nil {
+		return err
+	}
+	if boneData.scaleY, err = boneMap.float("scaleY", 1); err != nil {
+		return err
+	}
+	if boneData.skinRequired, err = boneMap.bool("skin", false); err != nil {
+		return err
+	}
+
+	r.data.addBone(boneData)
+	return nil
+}
 
-		if y, ok := bone.Y.(float64); ok {
-			boneData.y = float32(y) * scale
+func (r *skeletonReader) readSlots(root *jsonObject) error {
+	slots, err := root.array("slots")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < slots.len(); i++ {
+		if err := r.skip(r.readSlot(slots, i)); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if rotation, ok := bone.Rotation.(float64); ok {
-			boneData.rotation = float32(rotation)
+func (r *skeletonReader) readSlot(slots *jsonArray, i int) error {
+	slotMap, err := slots.object(i)
+	if err != nil {
+		return err
+	}
+	name, err := slotMap.requiredString("name")
+	if err != nil {
+		return err
+	}
+	boneName, err := slotMap.requiredString("bone")
+	if err != nil {
+		return err
+	}
+	_, boneData := r.data.FindBone(boneName)
+	if boneData == nil {
+		return loadError(slotMap.pathTo("bone"), "slot bone not found: %s", boneName)
+	}
+	slotData := NewSlotData(name, boneData)
+
+	if slotMap.has("color") {
+		c, err := readColor(slotMap, "color")
+		if err != nil {
+			return err
 		}
+		slotData.r = c[0]
+		slotData.g = c[1]
+		slotData.b = c[2]
+		slotData.a = c[3]
+	}
 
-		boneData.scaleX = 1
-		if scaleX, ok := bone.ScaleX.(float64); ok {
-			boneData.scaleX = float32(scaleX)
+	if slotData.attachmentName, err = slotMap.string("attachment", ""); err != nil {
+		return err
+	}
+
+	r.data.addSlot(slotData)
+	return nil
+}
+
+// readSkins reads the skins section, which is an array of named skins since
+// 3.8 and an object keyed by skin name before that.
+func (r *skeletonReader) readSkins(root *jsonObject) error {
+	if !root.has("skins") {
+		return nil
+	}
+	if r.format.skinsArray {
+		skins, err := root.array("skins")
+		if err != nil {
+			return err
 		}
+		for i := 0; i < skins.len(); i++ {
+			skinMap, err := skins.object(i)
+			if err == nil {
+				err = r.readSkinMap(skinMap)
+			}
+			if err := r.skip(err); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-		boneData.scaleY = 1
-		if scaleY, ok := bone.ScaleY.(float64); ok {
-			boneData.scaleY = float32(scaleY)
+	skins, err := root.object("skins")
+	if err != nil {
+		return err
+	}
+	for _, name := range skins.keys() {
+		attachments, err := skins.object(name)
+		if err == nil {
+			err = r.readSkin(NewSkin(name), attachments)
+		}
+		if err := r.skip(err); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		skeletonData.bones = append(skeletonData.bones, boneData)
+// readSkinMap reads a skin in the array layout, which can also list the
+// bones and constraints that only exist while the skin is active.
+func (r *skeletonReader) readSkinMap(skinMap *jsonObject) error {
+	name, err := skinMap.requiredString("name")
+	if err != nil {
+		return err
 	}
+	skin := NewSkin(name)
 
-	// Slots
-	for _, slot := range root.Slots {
-		boneName := slot.Bone
-		_, boneData := skeletonData.findBone(boneName)
-		if boneData == nil {
-			return nil, errors.New("spine: slot bone not found: " + boneName)
+	bones, err := skinMap.array("bones")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < bones.len(); i++ {
+		boneName, err := bones.string(i)
+		if err != nil {
+			return err
 		}
-		slotData := NewSlotData(slot.Name, boneData)
+		_, bone := r.data.FindBone(boneName)
+		if bone == nil {
+			if err := r.skip(loadError(bones.pathTo(i), "skin bone not found: %s", boneName)); err != nil {
+				return err
+			}
+			continue
+		}
+		skin.AddBone(bone)
+	}
 
-		if color := slot.Color; color != "" {
-			c, err := toColor(color)
+	for _, key := range []string{"ik", "transform", "path", "physics"} {
+		constraints, err := skinMap.array(key)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < constraints.len(); i++ {
+			constraintName, err := constraints.string(i)
 			if err != nil {
-				return nil, errors.New("spine: failed to parse color: " + err.Error())
+				return err
 			}
-			slotData.r = c[0]
-			slotData.g = c[1]
-			slotData.b = c[2]
-			slotData.a = c[3]
+			skin.AddConstraint(constraintName)
 		}
+	}
 
-		slotData.attachmentName = slot.Attachment
-
-		skeletonData.slots = append(skeletonData.slots, slotData)
+	attachments, err := skinMap.object("attachments")
+	if err != nil {
+		return err
 	}
+	return r.readSkin(skin, attachments)
+}
 
-	for skinName, skinMap := range root.Skins {
-		skin := NewSkin(skinName)
-		for slotName, slotMap := range skinMap {
-			slotIndex, _ := skeletonData.findSlot(slotName)
-			for name, at := range slotMap {
-				atName := name
-				if at.Name != "" {
-					atName = at.Name
+func (r *skeletonReader) readSkin(skin *Skin, slots *jsonObject) error {
+	if slots != nil {
+		for _, slotName := range slots.keys() {
+			slotIndex, _ := r.data.FindSlot(slotName)
+			if slotIndex == -1 {
+				if err := r.skip(loadError(slots.pathTo(slotName), "skin slot not found: %s", slotName)); err != nil {
+					return err
 				}
-
-				attachment, err := loader.NewAttachment(skin, at.Type, atName)
+				continue
+			}
+			slotMap, err := slots.object(slotName)
+			if err != nil {
+				return err
+			}
+			for _, attachmentName := range slotMap.keys() {
+				attachmentMap, err := slotMap.object(attachmentName)
 				if err != nil {
-					return nil, err
+					if err := r.skip(err); err != nil {
+						return err
+					}
+					continue
 				}
-				if regionAttach, ok := attachment.(*RegionAttachment); ok {
-					readAttachment(regionAttach, at, scale)
+				attachment, err := r.readAttachment(skin, attachmentName, attachmentMap)
+				if err != nil {
+					if err := r.skip(err); err != nil {
+						return err
+					}
+					continue
 				}
-				skin.AddAttachment(slotIndex, name, attachment)
+				skin.AddAttachment(slotIndex, attachmentName, attachment)
 			}
 		}
-		skeletonData.skins = append(skeletonData.skins, skin)
-		if skin.name == "default" {
-			skeletonData.defaultSkin = skin
-		}
-	}
-
-	for animName, fileAnim := range root.Animations {
-		timelines := make([]Timeline, 0)
-		duration := float32(0)
-		for boneName, timelineMap := range fileAnim.Bones {
-			boneIndex, _ := skeletonData.findBone(boneName)
-			for timelineType, timelineData := range timelineMap {
-				if timelineType == "rotate" {
-					n := len(timelineData)
-					timeline := NewRotateTimeline(n)
-					timeline.boneIndex = boneIndex
-					for i, valueMap := range timelineData {
-						time := float32(valueMap["time"].(float64))
-						angle := float32(valueMap["angle"].(float64))
-						timeline.setFrame(i, time, angle)
-						if curve, ok := valueMap["curve"]; ok {
-							readCurve(timeline.curve, i, curve)
-						}
-					}
-					duration = float32(math.Max(float64(duration), float64(timeline.frames[timeline.frameCount()*2-2])))
+	}
+	r.data.addSkin(skin)
+	return nil
+}
 
+func (r *skeletonReader) readAttachment(skin *Skin, name string, attachmentMap *jsonObject) (Attachment, error) {
+	name, err := attachmentMap.string("name", name)
+	if err != nil {
+		return nil, err
+	}
+	path, err := attachmentMap.string("path", name)
+	if err != nil {
+		return nil, err
+	}
+	_type, err := attachmentMap.string("type", "")
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := r.loader.NewAttachment(skin, _type, path)
+	if err != nil {
+		return nil, &LoadError{Path: attachmentMap.path, Err: err}
+	}
+	if region, ok := attachment.(*RegionAttachment); ok {
+		if err := r.readRegion(region, attachmentMap); err != nil {
+			return nil, err
+		}
+	}
+	return attachment, nil
+}
+
+func (r *skeletonReader) readRegion(attachment *RegionAttachment, attachmentMap *jsonObject) error {
+	var err error
+	if attachment.X, err = attachmentMap.float("x", 0); err != nil {
+		return err
+	}
+	attachment.X *= r.scale
+	if attachment.Y, err = attachmentMap.float("y", 0); err != nil {
+		return err
+	}
+	attachment.Y *= r.scale
+	if attachment.Rotation, err = attachmentMap.float("rotation", 0); err != nil {
+		return err
+	}
+	if attachment.ScaleX, err = attachmentMap.float("scaleX", 1); err != nil {
+		return err
+	}
+	if attachment.ScaleY, err = attachmentMap.float("scaleY", 1); err != nil {
+		return err
+	}
+	if attachment.Width, err = attachmentMap.float("width", 32); err != nil {
+		return err
+	}
+	attachment.Width *= r.scale
+	if attachment.Height, err = attachmentMap.float("height", 32); err != nil {
+		return err
+	}
+	attachment.Height *= r.scale
+	attachment.updateOffset()
+	return nil
+}
+
+func (r *skeletonReader) readEvents(root *jsonObject) error {
+	events, err := root.object("events")
+	if err != nil || events == nil {
+		return err
+	}
+	for _, name := range events.keys() {
+		eventMap, err := events.object(name)
+		if err == nil && eventMap == nil {
+			eventMap = &jsonObject{path: events.pathTo(name)}
+		}
+		if err == nil {
+			err = r.readEvent(name, eventMap)
+		}
+		if err := r.skip(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *skeletonReader) readEvent(name string, eventMap *jsonObject) error {
+	eventData := NewEventData(name)
+	i, err := eventMap.float("int", 0)
+	if err != nil {
+		return err
+	}
+	eventData.Int = int(i)
+	if eventData.Float, err = eventMap.float("float", 0); err != nil {
+		return err
+	}
+	if eventData.String, err = eventMap.string("string", ""); err != nil {
+		return err
+	}
+	if eventData.AudioPath, err = eventMap.string("audio", ""); err != nil {
+		return err
+	}
+	if eventData.Volume, err = eventMap.float("volume", 1); err != nil {
+		return err
+	}
+	if eventData.Balance, err = eventMap.float("balance", 0); err != nil {
+		return err
+	}
+	r.data.addEvent(eventData)
+	return nil
+}
+
+func (r *skeletonReader) readAnimations(root *jsonObject) error {
+	animations, err := root.object("animations")
+	if err != nil || animations == nil {
+		return err
+	}
+	for _, name := range animations.keys() {
+		animationMap, err := animations.object(name)
+		if err != nil {
+			if err := r.skip(err); err != nil {
+				return err
+			}
+			continue
+		}
+		animation, err := r.readAnimation(name, animationMap)
+		if err != nil {
+			if err := r.skip(err); err != nil {
+				return err
+			}
+			continue
+		}
+		r.data.addAnimation(animation)
+	}
+	return nil
+}
+
+func (r *skeletonReader) readAnimation(name string, animationMap *jsonObject) (*Animation, error) {
+	timelines := make([]Timeline, 0)
+	duration := float32(0)
+
+	for _, section := range animationMap.keys() {
+		if section != "bones" && section != "slots" && section != "events" {
+			if err := r.unknown(animationMap.pathTo(section), section, r.format.unsupportedAnimationSections, "animation section"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	bones, err := animationMap.object("bones")
+	if err != nil {
+		return nil, err
+	}
+	if bones != nil {
+		for _, boneName := range bones.keys() {
+			boneIndex, _ := r.data.FindBone(boneName)
+			if boneIndex == -1 {
+				if err := r.skip(loadError(bones.pathTo(boneName), "timeline bone not found: %s", boneName)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			timelineMap, err := bones.object(boneName)
+			if err != nil {
+				return nil, err
+			}
+			for _, timelineName := range timelineMap.keys() {
+				var timeline Timeline
+				var end float32
+				switch timelineName {
+				case "rotate":
+					timeline, end, err = r.readRotateTimeline(boneIndex, timelineMap, timelineName)
+				case "translate", "scale":
+					timeline, end, err = r.readTranslateTimeline(boneIndex, timelineMap, timelineName)
+				default:
+					err = r.unknown(timelineMap.pathTo(timelineName), timelineName, r.format.unsupportedBoneTimelines, "bone timeline")
+				}
+				if err := r.skip(err); err != nil {
+					return nil, err
+				}
+				if timeline != nil {
 					timelines = append(timelines, timeline)
-				} else if timelineType == "translate" {
-					n := len(timelineData)
-					timeline := NewTranslateTimeline(n)
-					timeline.boneIndex = boneIndex
-					for i, valueMap := range timelineData {
-						x := float32(0)
-						if xx, ok := valueMap["x"].(float64); ok {
-							x = float32(xx) * scale
-						}
-						y := float32(0)
-						if yy, ok := valueMap["y"].(float64); ok {
-							y = float32(yy) * scale
-						}
-						time := float32(valueMap["time"].(float64))
-
-						timeline.setFrame(i, time, x, y)
-						if curve, ok := valueMap["curve"]; ok {
-							readCurve(timeline.curve, i, curve)
-						}
-					}
-					duration = float32(math.Max(float64(duration), float64(timeline.frames[timeline.frameCount()*3-3])))
-					timelines = append(timelines, timeline)
-				} else if timelineType == "scale" {
-					n := len(timelineData)
-					timeline := NewScaleTimeline(n)
-					timeline.boneIndex = boneIndex
-					for i, valueMap := range timelineData {
-						x := float32(0)
-						if xx, ok := valueMap["x"].(float64); ok {
-							x = float32(xx)
-						}
-						y := float32(0)
-						if yy, ok := valueMap["y"].(float64); ok {
-							y = float32(yy)
-						}
-						time := float32(valueMap["time"].(float64))
-
-						timeline.setFrame(i, time, x, y)
-						if curve, ok := valueMap["curve"]; ok {
-							readCurve(timeline.curve, i, curve)
-						}
-					}
-					duration = float32(math.Max(float64(duration), float64(timeline.frames[timeline.frameCount()*3-3])))
-					timelines = append(timelines, timeline)
+					duration = float32(math.Max(float64(duration), float64(end)))
 				}
 			}
 		}
-		for slotName, timelineMap := range fileAnim.Slots {
-			slotIndex, _ := skeletonData.findSlot(slotName)
-
-			for timelineName, values := range timelineMap {
-				n := len(values)
-				if timelineName == "color" {
-					timeline := NewColorTimeline(n)
-					timeline.slotIndex = slotIndex
-
-					for frameIndex, valueMap := range values {
-						time := float32(valueMap["time"].(float64))
-						c, err := toColor(valueMap["color"].(string))
-						if err != nil {
-							return nil, errors.New("spine: failed to parse color: " + err.Error())
-						}
-						timeline.setFrame(frameIndex, time, c[0], c[1], c[2], c[3])
-						readCurve(timeline.curve, frameIndex, valueMap)
-					}
-					duration = float32(math.Max(float64(duration), float64(timeline.frames[timeline.frameCount()*5-5])))
-					timelines = append(timelines, timeline)
-				} else if timelineName == "attachment" {
-					timeline := NewAttachmentTimeline(n)
-					timeline.slotIndex = slotIndex
+	}
 
-					for frameIndex, valueMap := range values {
-						time := float32(valueMap["time"].(float64))
-						timeline.setFrame(frameIndex, time, valueMap["name"].(string))
-					}
-					duration = float32(math.Max(float64(duration), float64(timeline.frames[timeline.frameCount()-1])))
+	slots, err := animationMap.object("slots")
+	if err != nil {
+		return nil, err
+	}
+	if slots != nil {
+		for _, slotName := range slots.keys() {
+			slotIndex, _ := r.data.FindSlot(slotName)
+			if slotIndex == -1 {
+				if err := r.skip(loadError(slots.pathTo(slotName), "timeline slot not found: %s", slotName)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			timelineMap, err := slots.object(slotName)
+			if err != nil {
+				return nil, err
+			}
+			for _, timelineName := range timelineMap.keys() {
+				var timeline Timeline
+				var end float32
+				switch timelineName {
+				case r.format.colorTimeline:
+					timeline, end, err = r.readColorTimeline(slotIndex, timelineMap, timelineName)
+				case "attachment":
+					timeline, end, err = r.readAttachmentTimeline(slotIndex, timelineMap, timelineName)
+				default:
+					err = r.unknown(timelineMap.pathTo(timelineName), timelineName, r.format.unsupportedSlotTimelines, "slot timeline")
+				}
+				if err := r.skip(err); err != nil {
+					return nil, err
+				}
+				if timeline != nil {
 					timelines = append(timelines, timeline)
+					duration = float32(math.Max(float64(duration), float64(end)))
 				}
 			}
 		}
-		anim := NewAnimation(animName, timelines, duration)
-		skeletonData.animations = append(skeletonData.animations, anim)
 	}
 
-	return skeletonData, nil
+	if animationMap.has("events") {
+		timeline, end, err := r.readEventTimeline(animationMap)
+		if err := r.skip(err); err != nil {
+			return nil, err
+		}
+		if timeline != nil {
+			timelines = append(timelines, timeline)
+			duration = float32(math.Max(float64(duration), float64(end)))
+		}
+	}
+
+	return NewAnimation(name, timelines, duration), nil
 }
 
-func toColor(colorStr string) (c [4]float32, err error) {
-	for i := 0; i < len(c); i++ {
-		var b uint64
-		if b, err = strconv.ParseUint(colorStr[i*2:(i+1)*2], 16, 8); err != nil {
-			return
+// unknown handles a key the loader has no reader for. Keys the format is
+// known to use for features this runtime lacks are errors in strict mode,
+// since ignoring them loses motion, and warnings in lenient mode; anything
+// else is an error.
+func (r *skeletonReader) unknown(path, key string, unsupported []string, what string) error {
+	for _, name := range unsupported {
+		if key == name {
+			return r.skip(loadError(path, "unsupported %s: %s", what, key))
 		}
-		c[i] = float32(b) / 255.0
 	}
-	return
+	return loadError(path, "unknown %s: %s", what, key)
 }
 
-func readAttachment(attachment *RegionAttachment, at fileAttachment, scale float32) {
-	if x, ok := at.X.(float64); ok {
-		attachment.X = float32(x) * scale
+func (r *skeletonReader) readFrames(timelineMap *jsonObject, timelineName string) (*jsonArray, error) {
+	values, err := timelineMap.array(timelineName)
+	if err != nil {
+		return nil, err
 	}
+	if values.len() == 0 {
+		return nil, loadError(timelineMap.pathTo(timelineName), "timeline has no frames")
+	}
+	return values, nil
+}
 
-	if y, ok := at.Y.(float64); ok {
-		attachment.Y = float32(y) * scale
+func (r *skeletonReader) readTime(valueMap *jsonObject) (float32, error) {
+	if r.format.optionalTime {
+		return valueMap.float("time", 0)
 	}
+	return valueMap.requiredFloat("time")
+}
 
-	if rotation, ok := at.Rotation.(float64); ok {
-		attachment.Rotation = float32(rotation)
+func (r *skeletonReader) readRotateTimeline(boneIndex int, timelineMap *jsonObject, timelineName string) (Timeline, float32, error) {
+	values, err := r.readFrames(timelineMap, timelineName)
+	if err != nil {
+		return nil, 0, err
 	}
+	n := values.len()
+	timeline := NewRotateTimeline(n)
+	timeline.boneIndex = boneIndex
+	valueMaps := make([]*jsonObject, n)
+	for i := range valueMaps {
+		valueMap, err := values.object(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		time, err := r.readTime(valueMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		angle, err := valueMap.float(r.format.rotateKey, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		timeline.setFrame(i, time, angle)
+		valueMaps[i] = valueMap
+	}
+	frames := timeline.frames
+	for i := 0; i < n-1; i++ {
+		if err := r.format.readCurve(timeline.curve, i, valueMaps[i], frames[i*2], frames[i*2+2], frames[i*2+1:i*2+2], frames[i*2+3:i*2+4]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return timeline, frames[n*2-2], nil
+}
 
-	attachment.ScaleX = 1
-	if scaleX, ok := at.ScaleX.(float64); ok {
-		attachment.ScaleX = float32(scaleX)
+// readTranslateTimeline reads translate and scale timelines, which share a
+// frame layout.
+func (r *skeletonReader) readTranslateTimeline(boneIndex int, timelineMap *jsonObject, timelineName string) (Timeline, float32, error) {
+	values, err := r.readFrames(timelineMap, timelineName)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := values.len()
+	var timeline Timeline
+	var frames []float32
+	var curve *Curve
+	valueScale, valueDefault := r.scale, float32(0)
+	if timelineName == "translate" {
+		translate := NewTranslateTimeline(n)
+		translate.boneIndex = boneIndex
+		timeline, frames, curve = translate, translate.frames, translate.curve
+	} else {
+		scale := NewScaleTimeline(n)
+		scale.boneIndex = boneIndex
+		timeline, frames, curve = scale, scale.frames, scale.curve
+		valueScale, valueDefault = 1, r.format.scaleDefault
 	}
 
-	attachment.ScaleY = 1
-	if scaleY, ok := at.ScaleY.(float64); ok {
-		attachment.ScaleY = float32(scaleY)
+	valueMaps := make([]*jsonObject, n)
+	for i := range valueMaps {
+		valueMap, err := values.object(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		time, err := r.readTime(valueMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		x, err := valueMap.float("x", valueDefault)
+		if err != nil {
+			return nil, 0, err
+		}
+		y, err := valueMap.float("y", valueDefault)
+		if err != nil {
+			return nil, 0, err
+		}
+		frames[i*3] = time
+		frames[i*3+1] = x * valueScale
+		frames[i*3+2] = y * valueScale
+		valueMaps[i] = valueMap
 	}
+	for i := 0; i < n-1; i++ {
+		if err := r.format.readCurve(curve, i, valueMaps[i], frames[i*3], frames[i*3+3], unscaled(frames[i*3+1:i*3+3], valueScale), unscaled(frames[i*3+4:i*3+6], valueScale)); err != nil {
+			return nil, 0, err
+		}
+	}
+	return timeline, frames[n*3-3], nil
+}
 
-	attachment.Width = 32
-	if width, ok := at.Width.(float64); ok {
-		attachment.Width = float32(width) * scale
+func (r *skeletonReader) readColorTimeline(slotIndex int, timelineMap *jsonObject, timelineName string) (Timeline, float32, error) {
+	values, err := r.readFrames(timelineMap, timelineName)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := values.len()
+	timeline := NewColorTimeline(n)
+	timeline.slotIndex = slotIndex
+	valueMaps := make([]*jsonObject, n)
+	for i := range valueMaps {
+		valueMap, err := values.object(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		time, err := r.readTime(valueMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		c, err := readColor(valueMap, "color")
+		if err != nil {
+			return nil, 0, err
+		}
+		timeline.setFrame(i, time, c[0], c[1], c[2], c[3])
+		valueMaps[i] = valueMap
+	}
+	frames := timeline.frames
+	for i := 0; i < n-1; i++ {
+		if err := r.format.readCurve(timeline.curve, i, valueMaps[i], frames[i*5], frames[i*5+5], frames[i*5+1:i*5+5], frames[i*5+6:i*5+10]); err != nil {
+			return nil, 0, err
+		}
 	}
+	return timeline, frames[n*5-5], nil
+}
 
-	attachment.Height = 32
-	if height, ok := at.Height.(float64); ok {
-		attachment.Height = float32(height) * scale
+func (r *skeletonReader) readAttachmentTimeline(slotIndex int, timelineMap *jsonObject, timelineName string) (Timeline, float32, error) {
+	values, err := r.readFrames(timelineMap, timelineName)
+	if err != nil {
+		return nil, 0, err
 	}
-	attachment.updateOffset()
+	n := values.len()
+	timeline := NewAttachmentTimeline(n)
+	timeline.slotIndex = slotIndex
+	for i := 0; i < n; i++ {
+		valueMap, err := values.object(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		time, err := r.readTime(valueMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		name, err := valueMap.string("name", "")
+		if err != nil {
+			return nil, 0, err
+		}
+		timeline.setFrame(i, time, name)
+	}
+	timeline.resolve(r.data)
+	return timeline, timeline.frames[n-1], nil
 }
 
-func readCurve(curve *Curve, frameIndex int, data interface{}) {
-	switch t := data.(type) {
-	default:
-	case string:
-		if t == "stepped" {
-			curve.SetStepped(frameIndex)
+func (r *skeletonReader) readEventTimeline(animationMap *jsonObject) (Timeline, float32, error) {
+	values, err := r.readFrames(animationMap, "events")
+	if err != nil {
+		return nil, 0, err
+	}
+	n := values.len()
+	timeline := NewEventTimeline(n)
+	for i := 0; i < n; i++ {
+		valueMap, err := values.object(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		time, err := r.readTime(valueMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		name, err := valueMap.requiredString("name")
+		if err != nil {
+			return nil, 0, err
+		}
+		_, eventData := r.data.FindEvent(name)
+		if eventData == nil {
+			return nil, 0, loadError(valueMap.pathTo("name"), "event not found: %s", name)
+		}
+		event := NewEvent(time, eventData)
+		v, err := valueMap.float("int", float32(event.Int))
+		if err != nil {
+			return nil, 0, err
 		}
-	case []interface{}:
-		a := float32(t[0].(float64))
-		b := float32(t[1].(float64))
-		c := float32(t[2].(float64))
-		d := float32(t[3].(float64))
-		curve.SetCurve(frameIndex, a, b, c, d)
+		event.Int = int(v)
+		if event.Float, err = valueMap.float("float", event.Float); err != nil {
+			return nil, 0, err
+		}
+		if event.String, err = valueMap.string("string", event.String); err != nil {
+			return nil, 0, err
+		}
+		if event.Volume, err = valueMap.float("volume", event.Volume); err != nil {
+			return nil, 0, err
+		}
+		if event.Balance, err = valueMap.float("balance", event.Balance); err != nil {
+			return nil, 0, err
+		}
+		timeline.setFrame(i, event)
+	}
+	return timeline, timeline.frames[n-1], nil
+}
+
+func readColor(valueMap *jsonObject, key string) ([4]float32, error) {
+	s, err := valueMap.requiredString(key)
+	if err != nil {
+		return [4]float32{}, err
+	}
+	c, err := toColor(s)
+	if err != nil {
+		return c, loadError(valueMap.pathTo(key), "failed to parse color: %s", err.Error())
+	}
+	return c, nil
+}
+
+func toColor(colorStr string) (c [4]float32, err error) {
+	if len(colorStr) != 8 {
+		err = errors.New("expected 8 hex digits: " + colorStr)
+		return
+	}
+	for i := 0; i < len(c); i++ {
+		var b uint64
+		if b, err = strconv.ParseUint(colorStr[i*2:(i+1)*2], 16, 8); err != nil {
+			return
+		}
+		c[i] = float32(b) / 255.0
+	}
+	return
+}
+
+func unscaled(values []float32, scale float32) []float32 {
+	if scale == 1 {
+		return values
+	}
+	s := make([]float32, len(values))
+	for i, v := range values {
+		s[i] = v / scale
 	}
+	return s
 }
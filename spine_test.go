@@ -0,0 +1,248 @@
+package spine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testAtlas = `
+test.png
+format: RGBA8888
+filter: Linear,Linear
+repeat: none
+head
+  rotate: false
+  xy: 0, 0
+  size: 64, 64
+  orig: 64, 64
+  offset: 0, 0
+  index: -1
+torso
+  rotate: false
+  xy: 64, 0
+  size: 32, 64
+  orig: 32, 64
+  offset: 0, 0
+  index: -1
+hat
+  rotate: false
+  xy: 0, 64
+  size: 32, 16
+  orig: 32, 16
+  offset: 0, 0
+  index: -1
+`
+
+const testJSON = `{
+"bones": [
+  {"name": "root"},
+  {"name": "hip", "parent": "root", "y": 50, "length": 20},
+  {"name": "torso", "parent": "hip", "length": 40, "rotation": 90},
+  {"name": "head", "parent": "torso", "x": 40, "length": 30}
+],
+"slots": [
+  {"name": "torso", "bone": "torso", "attachment": "torso"},
+  {"name": "head", "bone": "head", "attachment": "head", "color": "ff0000ff"},
+  {"name": "hat", "bone": "head"}
+],
+"skins": {
+  "default": {
+    "torso": {"torso": {"x": 20, "width": 32, "height": 64}},
+    "head": {"head": {"x": 10, "width": 64, "height": 64}}
+  },
+  "fancy": {
+    "hat": {"hat": {"x": 40, "width": 32, "height": 16}}
+  }
+},
+"events": {"step": {"int": 1}, "shout": {"string": "hey"}},
+"animations": {
+  "walk": {
+    "events": [{"time": 0, "name": "step"}, {"time": 0.5, "name": "shout", "string": "ho"}, {"time": 1, "name": "step", "int": 2}],
+    "bones": {
+      "root": {
+        "translate": [{"time": 0, "x": 0, "y": 0}, {"time": 1, "x": 100, "y": 0}],
+        "rotate": [{"time": 0, "angle": 0}, {"time": 1, "angle": 10}]
+      },
+      "hip": {
+        "rotate": [{"time": 0, "angle": 0, "curve": [0.25, 0, 0.75, 1]}, {"time": 0.5, "angle": 20}, {"time": 1, "angle": 0}],
+        "scale": [{"time": 0, "x": 1, "y": 1}, {"time": 1, "x": 1.5, "y": 1}]
+      }
+    },
+    "slots": {
+      "head": {
+        "color": [{"time": 0, "color": "ffffffff"}, {"time": 1, "color": "ff000080"}],
+        "attachment": [{"time": 0, "name": "head"}, {"time": 0.5, "name": null}]
+      }
+    }
+  },
+  "idle": {
+    "bones": {
+      "torso": {"rotate": [{"time": 0, "angle": 0}, {"time": 2, "angle": 5}]}
+    }
+  }
+}
+}`
+
+type fakeTextures struct{}
+
+func (fakeTextures) Load(p *AtlasPage) error   { p.Width, p.Height = 256, 256; return nil }
+func (fakeTextures) Unload(p *AtlasPage) error { return nil }
+
+func loadJSON(t testing.TB, json string, options ...LoadOption) (*SkeletonData, error) {
+	t.Helper()
+	atlas, err := NewAtlas(strings.NewReader(testAtlas), fakeTextures{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(strings.NewReader(json), 1, AtlasAttachmentLoader{atlas}, options...)
+}
+
+func loadTest(t testing.TB) *SkeletonData {
+	t.Helper()
+	data, err := loadJSON(t, testJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLoadErrorPaths(t *testing.T) {
+	tests := []struct {
+		json, path string
+	}{
+		{strings.Replace(testJSON, `{"time": 1, "angle": 10}`, `{"time": "x", "angle": 10}`, 1), "animations.walk.bones.root.rotate[1].time"},
+		{strings.Replace(testJSON, `"ff0000ff"`, `"ff00"`, 1), "slots[1].color"},
+		{strings.Replace(testJSON, `[0.25, 0, 0.75, 1]`, `[0.25, 0]`, 1), "animations.walk.bones.hip.rotate[0].curve"},
+		{strings.Replace(testJSON, `"parent": "hip", "length": 40`, `"parent": 4, "length": 40`, 1), "bones[2].parent"},
+		{`{"bones": 3}`, "bones"},
+	}
+	for _, test := range tests {
+		_, err := loadJSON(t, test.json, WithLoadMode(Strict))
+		var loadErr *LoadError
+		if !errors.As(err, &loadErr) {
+			t.Errorf("want a LoadError at %s, got %v", test.path, err)
+			continue
+		}
+		if loadErr.Path != test.path {
+			t.Errorf("got path %q, want %q (%v)", loadErr.Path, test.path, err)
+		}
+	}
+}
+
+func TestLoadRejectsScale(t *testing.T) {
+	if _, err := New(strings.NewReader(testJSON), 0, AtlasAttachmentLoader{}); err == nil {
+		t.Fatal("want an error for scale 0")
+	}
+}
+
+func TestLoadUnsupported(t *testing.T) {
+	tests := []struct {
+		json, path string
+	}{
+		{strings.Replace(testJSON, `"rotate": [{"time": 0, "angle": 0}, {"time": 1, "angle": 10}]`, `"shear": [{"time": 0}]`, 1), "animations.walk.bones.root.shear"},
+		{strings.Replace(testJSON, `"attachment": [{"time": 0, "name": "head"}`, `"twoColor": [], "attachment": [{"time": 0, "name": "head"}`, 1), "animations.walk.slots.head.twoColor"},
+		{strings.Replace(testJSON, `"slots": {
+      "head"`, `"drawOrder": [], "slots": {
+      "head"`, 1), "animations.walk.drawOrder"},
+		{strings.Replace(testJSON, `"events": {"step"`, `"ik": [], "events": {"step"`, 1), "ik"},
+	}
+	for _, test := range tests {
+		_, err := loadJSON(t, test.json, WithLoadMode(Strict))
+		var loadErr *LoadError
+		if !errors.As(err, &loadErr) || loadErr.Path != test.path {
+			t.Errorf("strict: want an error at %s, got %v", test.path, err)
+		}
+		data, err := loadJSON(t, test.json)
+		if err != nil {
+			t.Errorf("lenient: %v", err)
+			continue
+		}
+		if warnings := data.Warnings(); len(warnings) != 1 || warnings[0].Path != test.path {
+			t.Errorf("lenient: want one warning at %s, got %v", test.path, warnings)
+		}
+	}
+}
+
+func TestLoadIgnoresUnsupportedByDefault(t *testing.T) {
+	json := strings.Replace(testJSON, `"events": {"step"`, `"ik": [{"name": "leg", "bones": ["hip", "torso"], "target": "head"}],
+"events": {"step"`, 1)
+	data, err := loadJSON(t, json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, walk := data.FindAnimation("walk"); walk == nil || len(data.Bones()) != 4 {
+		t.Error("the rest of the file was not loaded")
+	}
+	if warnings := data.Warnings(); len(warnings) != 1 || warnings[0].Path != "ik" {
+		t.Errorf("want one warning for the ik section, got %v", warnings)
+	}
+}
+
+func TestLoadSkinRequiredType(t *testing.T) {
+	json := strings.Replace(testJSON, `{"name": "hip", "parent": "root", "y": 50, "length": 20}`, `{"name": "hip", "parent": "root", "y": 50, "length": 20, "skin": "yes"}`, 1)
+	_, err := loadJSON(t, json, WithLoadMode(Strict))
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Path != "bones[1].skin" {
+		t.Fatalf("want an error at bones[1].skin, got %v", err)
+	}
+}
+
+func TestLoadMetadata(t *testing.T) {
+	json := strings.Replace(testJSON, `"bones": [`, `"skeleton": {"hash": "abc", "spine": "3.7.94", "x": -10, "y": -5, "width": 120, "height": 140, "fps": 24, "images": "./images/", "audio": "./audio/"},
+"bones": [`, 1)
+	data, err := loadJSON(t, json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Metadata{
+		Hash:       "abc",
+		Version:    "3.7.94",
+		X:          -10,
+		Y:          -5,
+		Width:      120,
+		Height:     140,
+		Fps:        24,
+		ImagesPath: "./images/",
+		AudioPath:  "./audio/",
+	}
+	if got := data.Metadata(); got != want {
+		t.Errorf("got metadata %+v, want %+v", got, want)
+	}
+	if fps := loadTest(t).Metadata().Fps; fps != 30 {
+		t.Errorf("got fps %v without a skeleton section, want 30", fps)
+	}
+}
+
+func TestLoadKeepsFileOrder(t *testing.T) {
+	json := strings.Replace(testJSON, `"default": {
+    "torso"`, `"zebra": {},
+  "default": {
+    "torso"`, 1)
+	data, err := loadJSON(t, json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, skin := range data.Skins() {
+		names = append(names, skin.Name())
+	}
+	for _, animation := range data.Animations() {
+		names = append(names, animation.Name())
+	}
+	for _, event := range data.Events() {
+		names = append(names, event.Name())
+	}
+	if got, want := strings.Join(names, " "), "zebra default fancy walk idle step shout"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// Within a bone, timelines keep the order of the file: the root's
+	// translation is listed before its rotation.
+	_, walk := data.FindAnimation("walk")
+	if _, ok := walk.timelines[0].(*TranslateTimeline); !ok {
+		t.Errorf("got %T first, want the root translation", walk.timelines[0])
+	}
+	if _, ok := walk.timelines[1].(*RotateTimeline); !ok {
+		t.Errorf("got %T second, want the root rotation", walk.timelines[1])
+	}
+}
@@ -0,0 +1,750 @@
+package spine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrParameterNotFound = errors.New("spine: parameter not found")
+	ErrStateNotFound     = errors.New("spine: state not found")
+)
+
+type ParameterType int
+
+const (
+	FloatParameter ParameterType = iota
+	BoolParameter
+	// TriggerParameter is a bool that is cleared when a transition that
+	// checks it is taken.
+	TriggerParameter
+)
+
+type parameterData struct {
+	name  string
+	kind  ParameterType
+	value float32
+	flag  bool
+}
+
+type stateKind int
+
+const (
+	animationState stateKind = iota
+	blend1DState
+	blend2DState
+)
+
+type blendClip struct {
+	animation *Animation
+	x, y      float32
+}
+
+type stateData struct {
+	name      string
+	kind      stateKind
+	animation *Animation
+	loop      bool
+	speed     float32
+
+	clips []blendClip
+	// Parameters giving the blend position: x alone for 1D blends.
+	xParameter, yParameter int
+}
+
+type compareOp int
+
+const (
+	opEqual compareOp = iota
+	opNotEqual
+	opLess
+	opLessEqual
+	opGreater
+	opGreaterEqual
+)
+
+var compareOps = map[string]compareOp{
+	"==": opEqual,
+	"!=": opNotEqual,
+	"<":  opLess,
+	"<=": opLessEqual,
+	">":  opGreater,
+	">=": opGreaterEqual,
+}
+
+type condition struct {
+	parameter int
+	op        compareOp
+	value     float32
+}
+
+type transitionData struct {
+	// from is -1 for transitions that can be taken from any state.
+	from, to   int
+	conditions []condition
+	// exitTime is the number of cycles of the from state that must have
+	// played before the transition can be taken, or -1 for none.
+	exitTime float32
+	duration float32
+}
+
+// StateMachineData is the definition of a state machine: its parameters, the
+// states that play animations or blend spaces, and the transitions between
+// them. It is loaded from JSON and shared by every StateMachine using it.
+type StateMachineData struct {
+	parameters     []*parameterData
+	parameterIndex map[string]int
+	states         []*stateData
+	stateIndex     map[string]int
+	transitions    []*transitionData
+	entry          int
+}
+
+// NewStateMachineData reads a state machine definition, resolving animation
+// names against the skeleton data. The definition looks like:
+//
+//	{
+//	  "parameters": {
+//	    "speed": {"type": "float", "default": 0},
+//	    "grounded": {"type": "bool", "default": true},
+//	    "attack": {"type": "trigger"}
+//	  },
+//	  "states": {
+//	    "idle": {"animation": "idle"},
+//	    "move": {"blend1d": {"parameter": "speed", "animations": [
+//	      {"animation": "walk", "position": 1}, {"animation": "run", "position": 3}
+//	    ]}},
+//	    "strafe": {"blend2d": {"x": "dx", "y": "dy", "animations": [
+//	      {"animation": "forward", "x": 0, "y": 1}, ...
+//	    ]}},
+//	    "swing": {"animation": "swing", "loop": false, "speed": 1.5}
+//	  },
+//	  "entry": "idle",
+//	  "transitions": [
+//	    {"from": "idle", "to": "move", "duration": 0.2,
+//	     "conditions": [{"parameter": "speed", "op": ">", "value": 0.1}]},
+//	    {"from": "*", "to": "swing", "conditions": [{"parameter": "attack"}]},
+//	    {"from": "swing", "to": "idle", "exitTime": 1, "duration": 0.1}
+//	  ]
+//	}
+//
+// Transitions are checked in order and from "*" can be taken from any other
+// state. Conditions on bool parameters compare with "==" or "!=" against
+// "value", true by default; conditions on triggers only name the trigger.
+// "exitTime" counts cycles of the from state, and "duration" is the
+// crossfade in seconds. The entry state defaults to the first state.
+func NewStateMachineData(r io.Reader, skeletonData *SkeletonData) (*StateMachineData, error) {
+	value, err := decodeJSON(json.NewDecoder(r))
+	if err != nil {
+		return nil, &LoadError{Err: errors.New("failed to parse state machine json: " + err.Error())}
+	}
+	root, err := newJSONObject("", value)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &stateMachineReader{
+		skeletonData: skeletonData,
+		data: &StateMachineData{
+			parameterIndex: make(map[string]int),
+			stateIndex:     make(map[string]int),
+		},
+	}
+	if err := reader.readParameters(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readStates(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readEntry(root); err != nil {
+		return nil, err
+	}
+	if err := reader.readTransitions(root); err != nil {
+		return nil, err
+	}
+	return reader.data, nil
+}
+
+type stateMachineReader struct {
+	skeletonData *SkeletonData
+	data         *StateMachineData
+}
+
+func (r *stateMachineReader) readParameters(root *jsonObject) error {
+	parameters, err := root.object("parameters")
+	if err != nil || parameters == nil {
+		return err
+	}
+	for _, name := range parameters.keys() {
+		parameterMap, err := parameters.object(name)
+		if err != nil {
+			return err
+		}
+		if parameterMap == nil {
+			return loadError(parameters.pathTo(name), "expected object, got null")
+		}
+		kind, err := parameterMap.requiredString("type")
+		if err != nil {
+			return err
+		}
+		parameter := &parameterData{name: name}
+		switch kind {
+		case "float":
+			parameter.kind = FloatParameter
+			parameter.value, err = parameterMap.float("default", 0)
+		case "bool":
+			parameter.kind = BoolParameter
+			parameter.flag, err = parameterMap.bool("default", false)
+		case "trigger":
+			parameter.kind = TriggerParameter
+		default:
+			return loadError(parameterMap.pathTo("type"), "unknown parameter type: %s", kind)
+		}
+		if err != nil {
+			return err
+		}
+		r.data.parameterIndex[name] = len(r.data.parameters)
+		r.data.parameters = append(r.data.parameters, parameter)
+	}
+	return nil
+}
+
+func (r *stateMachineReader) readStates(root *jsonObject) error {
+	states, err := root.object("states")
+	if err != nil {
+		return err
+	}
+	if states == nil || len(states.keys()) == 0 {
+		return loadError(root.pathTo("states"), "missing required states")
+	}
+	for _, name := range states.keys() {
+		stateMap, err := states.object(name)
+		if err != nil {
+			return err
+		}
+		if stateMap == nil {
+			return loadError(states.pathTo(name), "expected object, got null")
+		}
+		state, err := r.readState(name, stateMap)
+		if err != nil {
+			return err
+		}
+		r.data.stateIndex[name] = len(r.data.states)
+		r.data.states = append(r.data.states, state)
+	}
+	return nil
+}
+
+func (r *stateMachineReader) readState(name string, stateMap *jsonObject) (*stateData, error) {
+	state := &stateData{name: name}
+	var err error
+	if state.loop, err = stateMap.bool("loop", true); err != nil {
+		return nil, err
+	}
+	if state.speed, err = stateMap.float("speed", 1); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case stateMap.has("animation"):
+		state.kind = animationState
+		state.animation, err = r.animation(stateMap, "animation")
+		return state, err
+	case stateMap.has("blend1d"):
+		state.kind = blend1DState
+		blendMap, err := stateMap.object("blend1d")
+		if err != nil {
+			return nil, err
+		}
+		if state.xParameter, err = r.parameter(blendMap, "parameter", FloatParameter); err != nil {
+			return nil, err
+		}
+		return state, r.readClips(state, blendMap)
+	case stateMap.has("blend2d"):
+		state.kind = blend2DState
+		blendMap, err := stateMap.object("blend2d")
+		if err != nil {
+			return nil, err
+		}
+		if state.xParameter, err = r.parameter(blendMap, "x", FloatParameter); err != nil {
+			return nil, err
+		}
+		if state.yParameter, err = r.parameter(blendMap, "y", FloatParameter); err != nil {
+			return nil, err
+		}
+		return state, r.readClips(state, blendMap)
+	}
+	return nil, loadError(stateMap.path, "state needs an animation, blend1d or blend2d")
+}
+
+func (r *stateMachineReader) readClips(state *stateData, blendMap *jsonObject) error {
+	clips, err := blendMap.array("animations")
+	if err != nil {
+		return err
+	}
+	if clips.len() == 0 {
+		return loadError(blendMap.pathTo("animations"), "missing required animations")
+	}
+
+	// Building a blend space checks that no two animations share a point.
+	blend2D := NewBlendSpace2D()
+	for i := 0; i < clips.len(); i++ {
+		clipMap, err := clips.object(i)
+		if err != nil {
+			return err
+		}
+		var clip blendClip
+		if clip.animation, err = r.animation(clipMap, "animation"); err != nil {
+			return err
+		}
+		if state.kind == blend1DState {
+			if clip.x, err = clipMap.requiredFloat("position"); err != nil {
+				return err
+			}
+		} else {
+			if clip.x, err = clipMap.requiredFloat("x"); err != nil {
+				return err
+			}
+			if clip.y, err = clipMap.requiredFloat("y"); err != nil {
+				return err
+			}
+			if err := blend2D.Add(clip.animation, clip.x, clip.y); err != nil {
+				return &LoadError{Path: clipMap.path, Err: err}
+			}
+		}
+		state.clips = append(state.clips, clip)
+	}
+	return nil
+}
+
+func (r *stateMachineReader) readEntry(root *jsonObject) error {
+	name, err := root.string("entry", "")
+	if err != nil || name == "" {
+		return err
+	}
+	i, ok := r.data.stateIndex[name]
+	if !ok {
+		return loadError(root.pathTo("entry"), "state not found: %s", name)
+	}
+	r.data.entry = i
+	return nil
+}
+
+func (r *stateMachineReader) readTransitions(root *jsonObject) error {
+	transitions, err := root.array("transitions")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < transitions.len(); i++ {
+		transitionMap, err := transitions.object(i)
+		if err != nil {
+			return err
+		}
+		transition, err := r.readTransition(transitionMap)
+		if err != nil {
+			return err
+		}
+		r.data.transitions = append(r.data.transitions, transition)
+	}
+	return nil
+}
+
+func (r *stateMachineReader) readTransition(transitionMap *jsonObject) (*transitionData, error) {
+	transition := &transitionData{from: -1}
+	from, err := transitionMap.requiredString("from")
+	if err != nil {
+		return nil, err
+	}
+	if from != "*" {
+		if transition.from, err = r.state(transitionMap, "from"); err != nil {
+			return nil, err
+		}
+	}
+	if transition.to, err = r.state(transitionMap, "to"); err != nil {
+		return nil, err
+	}
+	if transition.exitTime, err = transitionMap.float("exitTime", -1); err != nil {
+		return nil, err
+	}
+	if transition.duration, err = transitionMap.float("duration", 0); err != nil {
+		return nil, err
+	}
+
+	conditions, err := transitionMap.array("conditions")
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < conditions.len(); i++ {
+		conditionMap, err := conditions.object(i)
+		if err != nil {
+			return nil, err
+		}
+		c, err := r.readCondition(conditionMap)
+		if err != nil {
+			return nil, err
+		}
+		transition.conditions = append(transition.conditions, c)
+	}
+	return transition, nil
+}
+
+func (r *stateMachineReader) readCondition(conditionMap *jsonObject) (condition, error) {
+	var c condition
+	var err error
+	if c.parameter, err = r.parameter(conditionMap, "parameter", -1); err != nil {
+		return c, err
+	}
+	parameter := r.data.parameters[c.parameter]
+	if parameter.kind == TriggerParameter {
+		return c, nil
+	}
+
+	op, err := conditionMap.string("op", "==")
+	if err != nil {
+		return c, err
+	}
+	var ok bool
+	if c.op, ok = compareOps[op]; !ok {
+		return c, loadError(conditionMap.pathTo("op"), "unknown comparison: %s", op)
+	}
+	if parameter.kind == FloatParameter {
+		c.value, err = conditionMap.requiredFloat("value")
+		return c, err
+	}
+	if c.op != opEqual && c.op != opNotEqual {
+		return c, loadError(conditionMap.pathTo("op"), "bool parameters only compare with == or !=")
+	}
+	value, err := conditionMap.bool("value", true)
+	if value {
+		c.value = 1
+	}
+	return c, err
+}
+
+func (r *stateMachineReader) animation(o *jsonObject, key string) (*Animation, error) {
+	name, err := o.requiredString(key)
+	if err != nil {
+		return nil, err
+	}
+	_, animation := r.skeletonData.FindAnimation(name)
+	if animation == nil {
+		return nil, loadError(o.pathTo(key), "animation not found: %s", name)
+	}
+	return animation, nil
+}
+
+// parameter returns the index of the parameter named at key, which must be
+// of the given type unless kind is -1.
+func (r *stateMachineReader) parameter(o *jsonObject, key string, kind ParameterType) (int, error) {
+	name, err := o.requiredString(key)
+	if err != nil {
+		return 0, err
+	}
+	i, ok := r.data.parameterIndex[name]
+	if !ok {
+		return 0, loadError(o.pathTo(key), "parameter not found: %s", name)
+	}
+	if kind != -1 && r.data.parameters[i].kind != kind {
+		return 0, loadError(o.pathTo(key), "parameter has the wrong type: %s", name)
+	}
+	return i, nil
+}
+
+func (r *stateMachineReader) state(o *jsonObject, key string) (int, error) {
+	name, err := o.requiredString(key)
+	if err != nil {
+		return 0, err
+	}
+	i, ok := r.data.stateIndex[name]
+	if !ok {
+		return 0, loadError(o.pathTo(key), "state not found: %s", name)
+	}
+	return i, nil
+}
+
+// StateMachine plays the states of a StateMachineData on a skeleton,
+// following transitions as its parameters change. Each skeleton needs its
+// own StateMachine.
+type StateMachine struct {
+	data   *StateMachineData
+	values []float32
+	flags  []bool
+	states []*stateInstance
+	// spares holds a second instance of states that transitioned to
+	// themselves, so that the fading out instance keeps its own time.
+	spares []*stateInstance
+
+	current int
+	// previous is the instance being faded out, or nil.
+	previous               *stateInstance
+	fadeTime, fadeDuration float32
+}
+
+type stateInstance struct {
+	data *stateData
+	time float32
+	// cycles is how many times the state has played through, fractions
+	// included, for exit times.
+	cycles  float32
+	blend1D *BlendSpace1D
+	blend2D *BlendSpace2D
+}
+
+func NewStateMachine(data *StateMachineData) *StateMachine {
+	m := &StateMachine{
+		data:    data,
+		values:  make([]float32, len(data.parameters)),
+		flags:   make([]bool, len(data.parameters)),
+		spares:  make([]*stateInstance, len(data.states)),
+		current: data.entry,
+	}
+	for i, parameter := range data.parameters {
+		m.values[i] = parameter.value
+		m.flags[i] = parameter.flag
+	}
+	for _, state := range data.states {
+		m.states = append(m.states, newStateInstance(state))
+	}
+	return m
+}
+
+func newStateInstance(state *stateData) *stateInstance {
+	instance := &stateInstance{data: state}
+	switch state.kind {
+	case blend1DState:
+		instance.blend1D = NewBlendSpace1D()
+		for _, clip := range state.clips {
+			instance.blend1D.Add(clip.animation, clip.x)
+		}
+	case blend2DState:
+		instance.blend2D = NewBlendSpace2D()
+		for _, clip := range state.clips {
+			instance.blend2D.Add(clip.animation, clip.x, clip.y)
+		}
+	}
+	return instance
+}
+
+func (m *StateMachine) Data() *StateMachineData {
+	return m.data
+}
+
+func (m *StateMachine) parameter(name string, kind ParameterType) (int, error) {
+	i, ok := m.data.parameterIndex[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrParameterNotFound, name)
+	}
+	if m.data.parameters[i].kind != kind {
+		return 0, errors.New("spine: parameter has the wrong type: " + name)
+	}
+	return i, nil
+}
+
+func (m *StateMachine) SetFloat(name string, value float32) error {
+	i, err := m.parameter(name, FloatParameter)
+	if err != nil {
+		return err
+	}
+	m.values[i] = value
+	return nil
+}
+
+func (m *StateMachine) SetBool(name string, value bool) error {
+	i, err := m.parameter(name, BoolParameter)
+	if err != nil {
+		return err
+	}
+	m.flags[i] = value
+	return nil
+}
+
+// SetTrigger sets the trigger until a transition that checks it is taken.
+func (m *StateMachine) SetTrigger(name string) error {
+	i, err := m.parameter(name, TriggerParameter)
+	if err != nil {
+		return err
+	}
+	m.flags[i] = true
+	return nil
+}
+
+// State returns the name of the current state. During a crossfade it is the
+// state being faded in.
+func (m *StateMachine) State() string {
+	return m.states[m.current].data.name
+}
+
+// SetState switches to the named state at once, without a crossfade.
+func (m *StateMachine) SetState(name string) error {
+	i, ok := m.data.stateIndex[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrStateNotFound, name)
+	}
+	m.current = i
+	m.previous = nil
+	m.states[i].reset()
+	return nil
+}
+
+// Update advances the playing states by dt seconds, then takes the first
+// transition whose exit time has passed and whose conditions hold.
+func (m *StateMachine) Update(dt float32) {
+	m.states[m.current].update(m, dt)
+	if m.previous != nil {
+		m.previous.update(m, dt)
+		m.fadeTime += dt
+		if m.fadeTime >= m.fadeDuration {
+			m.previous = nil
+		}
+	}
+
+	for _, transition := range m.data.transitions {
+		if transition.from == -1 && transition.to == m.current {
+			continue
+		}
+		if transition.from != -1 && transition.from != m.current {
+			continue
+		}
+		if m.ready(transition) {
+			m.start(transition)
+			break
+		}
+	}
+}
+
+func (m *StateMachine) ready(transition *transitionData) bool {
+	if transition.exitTime >= 0 && m.states[m.current].cycles < transition.exitTime {
+		return false
+	}
+	for _, c := range transition.conditions {
+		if !m.holds(c) {
+			return false
+		}
+	}
+	for _, c := range transition.conditions {
+		if m.data.parameters[c.parameter].kind == TriggerParameter {
+			m.flags[c.parameter] = false
+		}
+	}
+	return true
+}
+
+func (m *StateMachine) holds(c condition) bool {
+	var value float32
+	switch m.data.parameters[c.parameter].kind {
+	case FloatParameter:
+		value = m.values[c.parameter]
+	case BoolParameter:
+		if m.flags[c.parameter] {
+			value = 1
+		}
+	case TriggerParameter:
+		return m.flags[c.parameter]
+	}
+	switch c.op {
+	case opEqual:
+		return value == c.value
+	case opNotEqual:
+		return value != c.value
+	case opLess:
+		return value < c.value
+	case opLessEqual:
+		return value <= c.value
+	case opGreater:
+		return value > c.value
+	case opGreaterEqual:
+		return value >= c.value
+	}
+	return false
+}
+
+func (m *StateMachine) start(transition *transitionData) {
+	if transition.duration > 0 {
+		m.previous = m.states[m.current]
+		m.fadeTime = 0
+		m.fadeDuration = transition.duration
+	} else {
+		m.previous = nil
+	}
+	if transition.to == m.current && m.previous != nil {
+		// Fade out the playing instance while the spare one starts over.
+		spare := m.spares[m.current]
+		if spare == nil {
+			spare = newStateInstance(m.previous.data)
+		}
+		m.spares[m.current] = m.previous
+		m.states[m.current] = spare
+	}
+	m.current = transition.to
+	m.states[m.current].reset()
+}
+
+// Apply poses the skeleton with the current state, crossfading from the
+// previous one while a transition is in progress. Like Animation.Mix, it
+// only sets what the animations key, so the skeleton is usually set to the
+// setup pose first. It returns the error of a blend space state, if any.
+func (m *StateMachine) Apply(skeleton *Skeleton) error {
+	alpha := float32(1)
+	if m.previous != nil {
+		if err := m.previous.mix(m, skeleton, 1); err != nil {
+			return err
+		}
+		alpha = m.fadeTime / m.fadeDuration
+	}
+	return m.states[m.current].mix(m, skeleton, alpha)
+}
+
+func (s *stateInstance) reset() {
+	s.time = 0
+	s.cycles = 0
+	if s.blend1D != nil {
+		s.blend1D.SetPhase(0)
+	}
+	if s.blend2D != nil {
+		s.blend2D.SetPhase(0)
+	}
+}
+
+// blendSpace returns the state's blend space with its value taken from the
+// parameters, or nil for animation states.
+func (s *stateInstance) blendSpace(m *StateMachine) *blendSpace {
+	switch s.data.kind {
+	case blend1DState:
+		s.blend1D.SetValue(m.values[s.data.xParameter])
+		return &s.blend1D.blendSpace
+	case blend2DState:
+		s.blend2D.SetValue(m.values[s.data.xParameter], m.values[s.data.yParameter])
+		return &s.blend2D.blendSpace
+	}
+	return nil
+}
+
+func (s *stateInstance) update(m *StateMachine, dt float32) {
+	dt *= s.data.speed
+	if blend := s.blendSpace(m); blend != nil {
+		if duration := blend.Duration(); duration > 0 {
+			s.cycles += dt / duration
+		}
+		if !s.data.loop && s.cycles >= 1 {
+			// Hold the last frame rather than wrapping to the first.
+			blend.phase = 1
+			return
+		}
+		blend.Update(dt)
+		return
+	}
+	s.time += dt
+	if duration := s.data.animation.duration; duration > 0 {
+		s.cycles = s.time / duration
+	}
+}
+
+func (s *stateInstance) mix(m *StateMachine, skeleton *Skeleton, alpha float32) error {
+	if blend := s.blendSpace(m); blend != nil {
+		return blend.Mix(skeleton, alpha)
+	}
+	s.data.animation.Mix(skeleton, s.time, s.data.loop, alpha)
+	return nil
+}
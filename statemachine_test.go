@@ -0,0 +1,125 @@
+package spine
+
+import (
+	"strings"
+	"testing"
+)
+
+const testStateMachineJSON = `{
+"parameters": {
+  "speed": {"type": "float"},
+  "restart": {"type": "trigger"},
+  "once": {"type": "trigger"}
+},
+"states": {
+  "idle": {"animation": "idle"},
+  "move": {"blend1d": {"parameter": "speed", "animations": [{"animation": "walk", "position": 1}]}},
+  "once": {"loop": false, "blend1d": {"parameter": "speed", "animations": [{"animation": "walk", "position": 1}]}}
+},
+"transitions": [
+  {"from": "idle", "to": "move", "duration": 0.5, "conditions": [{"parameter": "speed", "op": ">", "value": 0.5}]},
+  {"from": "move", "to": "move", "duration": 0.5, "conditions": [{"parameter": "restart"}]},
+  {"from": "*", "to": "once", "conditions": [{"parameter": "once"}]},
+  {"from": "once", "to": "idle", "exitTime": 2}
+]
+}`
+
+func newTestStateMachine(t *testing.T) (*StateMachine, *Skeleton) {
+	t.Helper()
+	data := loadTest(t)
+	machineData, err := NewStateMachineData(strings.NewReader(testStateMachineJSON), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStateMachine(machineData), NewSkeleton(data)
+}
+
+// rootX applies the state machine to the setup pose and returns the root x.
+func rootX(t *testing.T, m *StateMachine, skeleton *Skeleton) float32 {
+	t.Helper()
+	skeleton.SetToSetupPose()
+	if err := m.Apply(skeleton); err != nil {
+		t.Fatal(err)
+	}
+	return skeleton.RootBone().X
+}
+
+func TestStateMachineTransition(t *testing.T) {
+	m, skeleton := newTestStateMachine(t)
+	m.Update(0.25)
+	if m.State() != "idle" {
+		t.Fatalf("got state %s, want idle", m.State())
+	}
+
+	if err := m.SetFloat("speed", 1); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(0.25)
+	if m.State() != "move" {
+		t.Fatalf("got state %s, want move", m.State())
+	}
+	// Halfway through the crossfade, walk is a quarter of the way through.
+	m.Update(0.25)
+	if x := rootX(t, m, skeleton); !near(x, 12.5) {
+		t.Errorf("got root x %v during the crossfade, want 12.5", x)
+	}
+	m.Update(0.25)
+	if x := rootX(t, m, skeleton); !near(x, 50) {
+		t.Errorf("got root x %v after the crossfade, want 50", x)
+	}
+
+	if err := m.SetBool("speed", true); err == nil {
+		t.Error("set a float parameter as a bool")
+	}
+	if err := m.SetState("missing"); err == nil {
+		t.Error("switched to a missing state")
+	}
+}
+
+func TestStateMachineSelfTransition(t *testing.T) {
+	m, skeleton := newTestStateMachine(t)
+	m.SetFloat("speed", 1)
+	m.SetState("move")
+	m.Update(0.5)
+	m.SetTrigger("restart")
+	m.Update(0)
+	// The instance fading out carries on from halfway while the new one
+	// starts over, so their crossfade is not a state blended with itself.
+	m.Update(0.25)
+	if x := rootX(t, m, skeleton); !near(x, 50) {
+		t.Errorf("got root x %v, want 50", x)
+	}
+	m.Update(0.25)
+	if x := rootX(t, m, skeleton); !near(x, 50) {
+		t.Errorf("got root x %v after the crossfade, want 50", x)
+	}
+
+	m.SetTrigger("restart")
+	m.Update(0)
+	m.SetTrigger("restart")
+	m.Update(0.25)
+	if x := rootX(t, m, skeleton); !near(x, 25) {
+		t.Errorf("got root x %v after restarting twice, want 25", x)
+	}
+}
+
+func TestStateMachineBlendStateLoop(t *testing.T) {
+	m, skeleton := newTestStateMachine(t)
+	m.SetTrigger("once")
+	m.Update(0)
+	if m.State() != "once" {
+		t.Fatalf("got state %s, want once", m.State())
+	}
+	m.Update(1.5)
+	if x := rootX(t, m, skeleton); !near(x, 100) {
+		t.Errorf("got root x %v, want the last frame at 100", x)
+	}
+	m.Update(0.25)
+	if m.State() != "once" {
+		t.Errorf("left the state after %s, before its exit time", m.State())
+	}
+	m.Update(0.25)
+	if m.State() != "idle" {
+		t.Errorf("got state %s after the exit time, want idle", m.State())
+	}
+}
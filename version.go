@@ -0,0 +1,174 @@
+package spine
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type curveStyle int
+
+const (
+	// "curve": [cx1, cy1, cx2, cy2], normalized to the frame.
+	curveArray curveStyle = iota
+	// "curve": cx1, "c2": cy1, "c3": cx2, "c4": cy2, normalized to the frame.
+	curveFields
+	// "curve": [cx1, cy1, cx2, cy2, ...] per channel, in timeline units.
+	curveAbsolute
+)
+
+// format describes how a particular editor version names and lays out the
+// fields the loader reads, so that every version loads into the same model.
+type format struct {
+	major, minor int
+
+	skinsArray    bool
+	optionalTime  bool
+	rotateKey     string
+	colorTimeline string
+	scaleDefault  float32
+	curves        curveStyle
+
+	// Keys used by features this runtime does not implement.
+	unsupportedSections          []string
+	unsupportedAnimationSections []string
+	unsupportedBoneTimelines     []string
+	unsupportedSlotTimelines     []string
+}
+
+// newFormat returns the format for files exported by the given editor
+// version. Files without a version predate the skeleton header and are read
+// with the 2.x layout.
+func newFormat(version string) (*format, error) {
+	f := &format{
+		rotateKey:     "angle",
+		colorTimeline: "color",
+		curves:        curveArray,
+
+		unsupportedSections:          []string{"ik", "transform", "path", "physics"},
+		unsupportedAnimationSections: []string{"draworder", "drawOrder", "ik", "transform", "path", "physics", "ffd", "deform", "attachments"},
+		unsupportedBoneTimelines:     []string{"flipX", "flipY", "shear", "translatex", "translatey", "scalex", "scaley", "shearx", "sheary", "inherit"},
+		unsupportedSlotTimelines:     []string{"twoColor", "rgb", "alpha", "rgba2", "rgb2", "sequence"},
+	}
+	if version == "" {
+		f.major = 2
+		return f, nil
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return nil, errors.New("spine: invalid editor version: " + version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, errors.New("spine: invalid editor version: " + version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, errors.New("spine: invalid editor version: " + version)
+	}
+	f.major, f.minor = major, minor
+
+	switch {
+	case major < 1 || major > 4 || (major == 4 && minor > 2):
+		return nil, errors.New("spine: unsupported editor version: " + version)
+	case major == 4:
+		f.skinsArray = true
+		f.optionalTime = true
+		f.rotateKey = "value"
+		f.colorTimeline = "rgba"
+		f.scaleDefault = 1
+		f.curves = curveAbsolute
+	case major == 3 && minor >= 8:
+		f.skinsArray = true
+		f.optionalTime = true
+		f.scaleDefault = 1
+		f.curves = curveFields
+	case major == 3:
+		f.scaleDefault = 1
+	}
+	return f, nil
+}
+
+// readCurve sets the curve between frameIndex and the next frame. The
+// values of each channel at both frames are needed to normalize curves that
+// are stored in timeline units.
+func (f *format) readCurve(curve *Curve, frameIndex int, valueMap *jsonObject, time1, time2 float32, values1, values2 []float32) error {
+	if frameIndex >= curve.FrameCount()-1 || !valueMap.has("curve") {
+		return nil
+	}
+	if t, ok := valueMap.value("curve").(string); ok {
+		if t == "stepped" {
+			curve.SetStepped(frameIndex)
+		}
+		return nil
+	}
+
+	switch f.curves {
+	case curveArray:
+		t, err := f.curveValues(valueMap, 4)
+		if err != nil {
+			return err
+		}
+		curve.SetCurve(frameIndex, t[0], t[1], t[2], t[3])
+	case curveFields:
+		cx1, err := valueMap.float("curve", 0)
+		if err != nil {
+			return err
+		}
+		cy1, err := valueMap.float("c2", 0)
+		if err != nil {
+			return err
+		}
+		cx2, err := valueMap.float("c3", 1)
+		if err != nil {
+			return err
+		}
+		cy2, err := valueMap.float("c4", 1)
+		if err != nil {
+			return err
+		}
+		curve.SetCurve(frameIndex, cx1, cy1, cx2, cy2)
+	case curveAbsolute:
+		t, err := f.curveValues(valueMap, len(values1)*4)
+		if err != nil {
+			return err
+		}
+		if time2 == time1 {
+			return nil
+		}
+		// Channels that do not change between the frames take the curve of
+		// the first channel that does, and the others get their own curve
+		// only if it differs from that one.
+		var first []float32
+		for channel := range values1 {
+			delta := values2[channel] - values1[channel]
+			if delta == 0 {
+				continue
+			}
+			a := (t[channel*4] - time1) / (time2 - time1)
+			b := (t[channel*4+1] - values1[channel]) / delta
+			c := (t[channel*4+2] - time1) / (time2 - time1)
+			d := (t[channel*4+3] - values1[channel]) / delta
+			switch {
+			case first == nil:
+				first = []float32{a, b, c, d}
+				curve.SetCurve(frameIndex, a, b, c, d)
+			case a != first[0] || b != first[1] || c != first[2] || d != first[3]:
+				curve.SetChannelCurve(channel, frameIndex, a, b, c, d)
+			}
+		}
+	}
+	return nil
+}
+
+func (f *format) curveValues(valueMap *jsonObject, n int) ([]float32, error) {
+	a, err := valueMap.array("curve")
+	if err != nil {
+		return nil, err
+	}
+	if a.len() < n {
+		return nil, loadError(a.path, "expected %d curve values, got %d", n, a.len())
+	}
+	return a.floats()
+}
@@ -0,0 +1,142 @@
+package spine
+
+import (
+	"strings"
+	"testing"
+)
+
+// formatJSON returns a skeleton exported by the given editor version with
+// a rotation eased by (0.25, 0, 0.75, 1), a scale key without y and a
+// stepped color key, laid out as that version does.
+func formatJSON(version string) string {
+	header := `"skeleton": {"spine": "` + version + `"},`
+	if version == "" {
+		header = ""
+	}
+	rotate := `{"time": 0, "angle": 0, "curve": [0.25, 0, 0.75, 1]}, {"time": 1, "angle": 90}`
+	color := `"color": [{"time": 0, "color": "ff000080", "curve": "stepped"}, {"time": 1, "color": "ffffffff"}]`
+	switch {
+	case strings.HasPrefix(version, "3.8"):
+		rotate = `{"angle": 0, "curve": 0.25, "c3": 0.75}, {"time": 1, "angle": 90}`
+	case strings.HasPrefix(version, "4."):
+		rotate = `{"value": 0, "curve": [0.25, 0, 0.75, 90]}, {"time": 1, "value": 90}`
+		color = `"rgba": [{"color": "ff000080", "curve": "stepped"}, {"time": 1, "color": "ffffffff"}]`
+	}
+	return `{` + header + `
+"bones": [{"name": "root"}],
+"slots": [{"name": "s", "bone": "root"}],
+"animations": {"a": {
+  "bones": {"root": {
+    "rotate": [` + rotate + `],
+    "scale": [{"time": 0, "x": 2}, {"time": 1, "x": 1, "y": 1}]
+  }},
+  "slots": {"s": {` + color + `}}
+}}
+}`
+}
+
+func TestLoadFormats(t *testing.T) {
+	tests := []struct {
+		version string
+		scaleY  float32
+	}{
+		{"", 0},
+		{"1.5.1", 0},
+		{"2.1.27", 0},
+		{"3.6.53", 1},
+		{"3.8.99", 1},
+		{"4.0.64", 1},
+		{"4.2.22", 1},
+	}
+	for _, test := range tests {
+		data, err := New(strings.NewReader(formatJSON(test.version)), 1, AtlasAttachmentLoader{})
+		if err != nil {
+			t.Errorf("version %q: %v", test.version, err)
+			continue
+		}
+		if got := data.Metadata().Version; got != test.version {
+			t.Errorf("version %q: got version %q", test.version, got)
+		}
+		_, a := data.FindAnimation("a")
+		var rotate *RotateTimeline
+		var scale *ScaleTimeline
+		var color *ColorTimeline
+		for _, timeline := range a.timelines {
+			switch timeline := timeline.(type) {
+			case *RotateTimeline:
+				rotate = timeline
+			case *ScaleTimeline:
+				scale = timeline
+			case *ColorTimeline:
+				color = timeline
+			}
+		}
+		if rotate == nil || scale == nil || color == nil {
+			t.Errorf("version %q: missing timelines", test.version)
+			continue
+		}
+
+		if rotate.frames[3] != 90 {
+			t.Errorf("version %q: got rotate frames %v", test.version, rotate.frames)
+		}
+		cx1, cy1, cx2, cy2 := bezierPoints(rotate.curve.curves, 0)
+		if !nearAll([4]float32{cx1, cy1, cx2, cy2}, [4]float32{0.25, 0, 0.75, 1}) {
+			t.Errorf("version %q: got curve %v, %v, %v, %v", test.version, cx1, cy1, cx2, cy2)
+		}
+		if scale.frames[1] != 2 || scale.frames[2] != test.scaleY {
+			t.Errorf("version %q: got scale %v, %v, want 2, %v", test.version, scale.frames[1], scale.frames[2], test.scaleY)
+		}
+		if !near(color.frames[4], 128.0/255) || !color.curve.isStepped(0) {
+			t.Errorf("version %q: got color frames %v", test.version, color.frames)
+		}
+	}
+}
+
+func TestLoadChannelCurveNormalization(t *testing.T) {
+	// x eases over 0 to 10 and y over 0 to 20 with the same normalized
+	// curve, so y shares x's curve. The unchanging scale y takes x's curve.
+	json := `{
+"skeleton": {"spine": "4.1.20"},
+"bones": [{"name": "root"}],
+"animations": {"a": {"bones": {"root": {
+  "translate": [
+    {"x": 0, "y": 0, "curve": [0.5, 0, 1.5, 10, 0.5, 0, 1.5, 20]},
+    {"time": 2, "x": 10, "y": 20}
+  ],
+  "scale": [
+    {"x": 1, "y": 1, "curve": [0.5, 1, 1.5, 3, 0, 0, 0, 0]},
+    {"time": 2, "x": 3, "y": 1}
+  ]
+}}}}
+}`
+	data, err := New(strings.NewReader(json), 1, AtlasAttachmentLoader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, a := data.FindAnimation("a")
+	for _, timeline := range a.timelines {
+		var curve *Curve
+		switch timeline := timeline.(type) {
+		case *TranslateTimeline:
+			curve = timeline.curve
+		case *ScaleTimeline:
+			curve = timeline.curve
+		}
+		if curve.Channels() != 1 {
+			t.Errorf("%T: got %d channel curves, want 1", timeline, curve.Channels())
+		}
+		cx1, cy1, cx2, cy2 := bezierPoints(curve.curves, 0)
+		if !nearAll([4]float32{cx1, cy1, cx2, cy2}, [4]float32{0.25, 0, 0.75, 1}) {
+			t.Errorf("%T: got curve %v, %v, %v, %v", timeline, cx1, cy1, cx2, cy2)
+		}
+	}
+}
+
+func TestLoadRejectsVersion(t *testing.T) {
+	for _, version := range []string{"5.0.1", "4.3.0", "0.9", "abc", "4"} {
+		_, err := New(strings.NewReader(formatJSON(version)), 1, AtlasAttachmentLoader{})
+		if err == nil {
+			t.Errorf("loaded editor version %q", version)
+		}
+	}
+}